@@ -0,0 +1,269 @@
+// Package diff produces unified diffs between two versions of a file.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around each change.
+const context = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	// a and b are the indexes of the line in the old and new version.
+	a, b int
+}
+
+// Unified returns a unified diff that turns old into new, labelling the two
+// sides with the given names. It returns an empty string if the contents are
+// equal.
+func Unified(oldName, newName string, old, new []byte) string {
+	if string(old) == string(new) {
+		return ""
+	}
+
+	a := splitLines(string(old))
+	b := splitLines(string(new))
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n", oldName)
+	fmt.Fprintf(&sb, "+++ %s\n", newName)
+
+	for _, hunk := range hunks(ops) {
+		writeHunk(&sb, a, b, hunk)
+	}
+
+	return sb.String()
+}
+
+// splitLines splits text into lines, keeping the line terminators.
+func splitLines(text string) []string {
+	var lines []string
+
+	for len(text) > 0 {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			lines = append(lines, text)
+			break
+		}
+		lines = append(lines, text[:i+1])
+		text = text[i+1:]
+	}
+
+	return lines
+}
+
+// diffLines computes the shortest edit script between a and b with the
+// linear-space variant of the Myers algorithm. Rather than keeping every
+// round of the search to walk back through, it finds the middle of an optimal
+// path by searching from both ends and recurses on either side of it, so
+// memory grows with the size of the input rather than the square of the edit.
+func diffLines(a, b []string) []op {
+	d := differ{a: a, b: b}
+	d.diff(0, len(a), 0, len(b))
+	return d.ops
+}
+
+type differ struct {
+	a, b []string
+	ops  []op
+}
+
+// diff appends the edit script that turns a[aLo:aHi] into b[bLo:bHi].
+func (d *differ) diff(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, op{opEqual, aLo, bLo})
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.ops = append(d.ops, op{opInsert, aLo, y})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.ops = append(d.ops, op{opDelete, x, bLo})
+		}
+	default:
+		x, y, u, v := middleSnake(d.a[aLo:aHi], d.b[bLo:bHi])
+		d.diff(aLo, aLo+x, bLo, bLo+y)
+		for i := range u - x {
+			d.ops = append(d.ops, op{opEqual, aLo + x + i, bLo + y + i})
+		}
+		d.diff(aLo+u, aHi, bLo+v, bHi)
+	}
+
+	for i := range suffix {
+		d.ops = append(d.ops, op{opEqual, aHi + i, bHi + i})
+	}
+}
+
+// middleSnake returns the start and end of the run of equal lines in the
+// middle of a shortest edit script between a and b, found by searching
+// forwards from the start and backwards from the end until the two meet.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// forward holds the furthest x reached on each diagonal from the start,
+	// backward the furthest distance reached from the end.
+	forward := make([]int, 2*maxD+3)
+	backward := make([]int, 2*maxD+3)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			if kb := delta - k; odd && kb >= -(d-1) && kb <= d-1 && x+backward[offset+kb] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if kf := delta - k; !odd && kf >= -d && kf <= d && x+forward[offset+kf] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+
+	// The searches always meet within maxD rounds.
+	panic("diff: no middle snake")
+}
+
+// hunks groups the edit script into ranges of operations, each holding one or
+// more changes and up to context unchanged lines around them.
+func hunks(ops []op) [][]op {
+	var (
+		result [][]op
+		start  = -1
+		end    int
+	)
+
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+
+		from := max(i-context, 0)
+		if start >= 0 && from <= end {
+			end = min(i+context+1, len(ops))
+			continue
+		}
+
+		if start >= 0 {
+			result = append(result, ops[start:end])
+		}
+		start, end = from, min(i+context+1, len(ops))
+	}
+
+	if start >= 0 {
+		result = append(result, ops[start:end])
+	}
+
+	return result
+}
+
+func writeHunk(sb *strings.Builder, a, b []string, hunk []op) {
+	var oldCount, newCount int
+
+	for _, o := range hunk {
+		switch o.kind {
+		case opEqual:
+			oldCount++
+			newCount++
+		case opDelete:
+			oldCount++
+		case opInsert:
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n",
+		hunkRange(hunk[0].a, oldCount),
+		hunkRange(hunk[0].b, newCount),
+	)
+
+	for _, o := range hunk {
+		switch o.kind {
+		case opEqual:
+			writeLine(sb, ' ', a[o.a])
+		case opDelete:
+			writeLine(sb, '-', a[o.a])
+		case opInsert:
+			writeLine(sb, '+', b[o.b])
+		}
+	}
+}
+
+// hunkRange formats the start and length of one side of a hunk. An empty side
+// refers to the line before the hunk, as in GNU diff.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func writeLine(sb *strings.Builder, prefix byte, line string) {
+	sb.WriteByte(prefix)
+	sb.WriteString(line)
+
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString("\n\\ No newline at end of file\n")
+	}
+}
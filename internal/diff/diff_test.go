@@ -0,0 +1,224 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "Equal",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name: "Change",
+			old:  "a\nb \nc\n",
+			new:  "a\nb\nc\n",
+			expected: `--- a/file.yaml
++++ b/file.yaml
+@@ -1,3 +1,3 @@
+ a
+-b 
++b
+ c
+`,
+		},
+		{
+			name: "Separate Hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\neleven\n12\n",
+			expected: `--- a/file.yaml
++++ b/file.yaml
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -8,5 +8,5 @@
+ 8
+ 9
+ 10
+-11
++eleven
+ 12
+`,
+		},
+		{
+			name: "Insert And Delete",
+			old:  "a\nb\nc\n",
+			new:  "x\na\nc\n",
+			expected: `--- a/file.yaml
++++ b/file.yaml
+@@ -1,3 +1,3 @@
++x
+ a
+-b
+ c
+`,
+		},
+		{
+			name: "No Newline At End",
+			old:  "a\nb ",
+			new:  "a\nb",
+			expected: `--- a/file.yaml
++++ b/file.yaml
+@@ -1,2 +1,2 @@
+ a
+-b 
+\ No newline at end of file
++b
+\ No newline at end of file
+`,
+		},
+		{
+			name: "Empty Old",
+			old:  "",
+			new:  "a\n",
+			expected: `--- a/file.yaml
++++ b/file.yaml
+@@ -0,0 +1 @@
++a
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Unified("a/file.yaml", "b/file.yaml", []byte(tt.old), []byte(tt.new))
+			assert.Equal(t, tt.expected, diff)
+		})
+	}
+}
+
+func TestUnifiedRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := range 200 {
+		old := randomLines(rng)
+		new := mutateLines(rng, old)
+
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			diff := Unified("a", "b", []byte(old), []byte(new))
+			assert.Equal(t, new, apply(t, old, diff))
+		})
+	}
+}
+
+func TestDiffLinesShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := range 200 {
+		a := splitLines(randomLines(rng))
+		b := splitLines(mutateLines(rng, strings.Join(a, "")))
+
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var edits int
+			for _, o := range diffLines(a, b) {
+				if o.kind != opEqual {
+					edits++
+				}
+			}
+			assert.Equal(t, len(a)+len(b)-2*longestCommon(a, b), edits)
+		})
+	}
+}
+
+// longestCommon returns the length of the longest common subsequence of a
+// and b.
+func longestCommon(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for _, x := range a {
+		cur := make([]int, len(b)+1)
+		for j, y := range b {
+			if x == y {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(cur[j], prev[j+1])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func randomLines(rng *rand.Rand) string {
+	var sb strings.Builder
+	for range rng.Intn(30) {
+		fmt.Fprintf(&sb, "line %d\n", rng.Intn(5))
+	}
+	return sb.String()
+}
+
+func mutateLines(rng *rand.Rand, text string) string {
+	lines := splitLines(text)
+
+	var out []string
+	for _, line := range lines {
+		switch rng.Intn(6) {
+		case 0:
+			continue
+		case 1:
+			out = append(out, "inserted\n", line)
+		case 2:
+			out = append(out, "changed "+line)
+		default:
+			out = append(out, line)
+		}
+	}
+
+	return strings.Join(out, "")
+}
+
+// apply applies a diff produced by Unified to text.
+func apply(t *testing.T, text, diff string) string {
+	t.Helper()
+
+	if diff == "" {
+		return text
+	}
+
+	old := splitLines(text)
+	var out []string
+	next := 0
+
+	for _, line := range splitLines(diff)[2:] {
+		switch line[0] {
+		case '@':
+			var oldStart int
+			_, err := fmt.Sscanf(line, "@@ -%d", &oldStart)
+			assert.NoError(t, err)
+
+			if !strings.HasPrefix(line, fmt.Sprintf("@@ -%d,0 ", oldStart)) {
+				oldStart--
+			}
+			out = append(out, old[next:oldStart]...)
+			next = oldStart
+		case ' ':
+			out = append(out, line[1:])
+			next++
+		case '-':
+			next++
+		case '+':
+			out = append(out, line[1:])
+		}
+	}
+
+	out = append(out, old[next:]...)
+	return strings.Join(out, "")
+}
@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FileDiff describes the changes a unified diff makes to one file.
+type FileDiff struct {
+	// OldName and NewName are the paths on either side, with the a/ and b/
+	// prefixes removed. They are empty for a file that is added or deleted.
+	OldName string
+	NewName string
+	// Added holds the 1-based numbers of the lines added or changed in the
+	// new version of the file, in ascending order.
+	Added []int
+}
+
+// Parse reads the file diffs from a unified diff, such as the output of git
+// diff or Unified. Anything outside of the file diffs is ignored.
+func Parse(r io.Reader) ([]FileDiff, error) {
+	var (
+		diffs   []FileDiff
+		current *FileDiff
+		newLine int
+		inHunk  bool
+		oldLeft int
+		newLeft int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if inHunk && (oldLeft > 0 || newLeft > 0) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				current.Added = append(current.Added, newLine)
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, " "), line == "":
+				newLine++
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, `\`):
+			default:
+				return nil, fmt.Errorf("unexpected line in hunk: %q", line)
+			}
+			continue
+		}
+		inHunk = false
+
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			diffs = append(diffs, FileDiff{OldName: diffName(line[4:])})
+			current = &diffs[len(diffs)-1]
+		case strings.HasPrefix(line, "+++ ") && current != nil:
+			current.NewName = diffName(line[4:])
+		case strings.HasPrefix(line, "@@ ") && current != nil:
+			var err error
+			newLine, oldLeft, newLeft, err = parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			inHunk = true
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" after the last line of a hunk.
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return diffs, nil
+}
+
+// parseHunkHeader parses a header like "@@ -1,3 +1,4 @@" and returns the
+// first line on the new side and the number of lines on either side.
+func parseHunkHeader(line string) (int, int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header: %q", line)
+	}
+
+	_, oldCount, err := parseRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header: %q: %w", line, err)
+	}
+
+	newStart, newCount, err := parseRange(fields[2][1:])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header: %q: %w", line, err)
+	}
+
+	return newStart, oldCount, newCount, nil
+}
+
+func parseRange(s string) (int, int, error) {
+	start, count, ok := strings.Cut(s, ",")
+
+	startNum, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !ok {
+		return startNum, 1, nil
+	}
+
+	countNum, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return startNum, countNum, nil
+}
+
+// diffName extracts the path from a --- or +++ line, dropping timestamps,
+// quoting and the a/ or b/ prefix. /dev/null becomes an empty name.
+func diffName(name string) string {
+	if i := strings.IndexByte(name, '\t'); i >= 0 {
+		name = name[:i]
+	}
+
+	if strings.HasPrefix(name, `"`) {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+	}
+
+	if name == "/dev/null" {
+		return ""
+	}
+
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		name = name[2:]
+	}
+
+	return name
+}
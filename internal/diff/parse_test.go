@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	const patch = `diff --git a/changed.yaml b/changed.yaml
+index 1111111..2222222 100644
+--- a/changed.yaml
++++ b/changed.yaml
+@@ -1,5 +1,6 @@
+ a: 1
+-b: 2
++b: 3
++c: 4
+ d: 5
+ e: 6
+ f: 7
+@@ -20,2 +21,0 @@
+-x: 1
+-y: 2
+diff --git a/new.yaml b/new.yaml
+new file mode 100644
+--- /dev/null
++++ b/new.yaml
+@@ -0,0 +1,2 @@
++a: 1
++b: 2
+\ No newline at end of file
+diff --git a/deleted.yaml b/deleted.yaml
+deleted file mode 100644
+--- a/deleted.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-a: 1
+--- "a/with space.yaml"	2024-01-01 00:00:00
++++ "b/with space.yaml"	2024-01-01 00:00:00
+@@ -3 +3 @@
+--- old
++++ new
+`
+
+	diffs, err := Parse(strings.NewReader(patch))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []FileDiff{
+		{OldName: "changed.yaml", NewName: "changed.yaml", Added: []int{2, 3}},
+		{OldName: "", NewName: "new.yaml", Added: []int{1, 2}},
+		{OldName: "deleted.yaml", NewName: ""},
+		{OldName: "with space.yaml", NewName: "with space.yaml", Added: []int{3}},
+	}, diffs)
+}
+
+func TestParseUnified(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\nten\neleven\n"
+
+	diffs, err := Parse(strings.NewReader(Unified("a/f.yaml", "b/f.yaml", []byte(old), []byte(new))))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []FileDiff{
+		{OldName: "f.yaml", NewName: "f.yaml", Added: []int{3, 10, 11}},
+	}, diffs)
+}
@@ -0,0 +1,117 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/cedws/yamllintx/lint"
+)
+
+const utf8BOM = "\uFEFF"
+
+// documentLines holds the lines of a document, without their terminators,
+// to convert the rune-based columns of the linter to the UTF-16 based
+// positions of the protocol.
+type documentLines struct {
+	lines []string
+	bom   bool
+}
+
+func splitLines(text string) documentLines {
+	bom := strings.HasPrefix(text, utf8BOM)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return documentLines{lines: lines, bom: bom}
+}
+
+// position converts a 1-based line and rune column to a protocol position.
+// Columns past the end of a line are clamped to it.
+func (d documentLines) position(line, column int) position {
+	if line > len(d.lines) {
+		last := len(d.lines) - 1
+		return position{Line: last, Character: utf16Len(d.lines[last])}
+	}
+
+	text := d.lines[line-1]
+
+	// Columns on the first line are counted from after the byte order mark.
+	var character int
+	if line == 1 && d.bom {
+		text = text[len(utf8BOM):]
+		character = 1
+	}
+
+	for i := range text {
+		if column <= 1 {
+			break
+		}
+		r, _ := utf8.DecodeRuneInString(text[i:])
+		character += utf16.RuneLen(r)
+		column--
+	}
+
+	return position{Line: line - 1, Character: character}
+}
+
+func (d documentLines) lspRange(line, column, endLine, endColumn int) lspRange {
+	return lspRange{
+		Start: d.position(line, column),
+		End:   d.position(endLine, endColumn),
+	}
+}
+
+// disableLine returns an edit that suppresses the rule on the 1-based line,
+// by extending a disable-line directive that already applies to it or by
+// adding one on the line above.
+func (d documentLines) disableLine(line int, rule string) textEdit {
+	directive := "# " + lint.DisableLineDirective
+
+	for _, candidate := range []int{line, line - 1} {
+		if candidate < 1 || candidate > len(d.lines) {
+			continue
+		}
+
+		text := d.lines[candidate-1]
+		trimmed := strings.TrimSpace(text)
+
+		// The line above only counts if the directive is all it holds.
+		if candidate == line-1 && !strings.HasPrefix(trimmed, directive) {
+			continue
+		}
+		if !strings.Contains(text, directive) {
+			continue
+		}
+
+		end := d.position(candidate, utf8.RuneCountInString(strings.TrimRight(text, " \t"))+1)
+		return textEdit{
+			Range:   lspRange{Start: end, End: end},
+			NewText: " rule:" + rule,
+		}
+	}
+
+	text := ""
+	if line <= len(d.lines) {
+		text = d.lines[line-1]
+	}
+	indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+
+	start := position{Line: line - 1}
+	return textEdit{
+		Range:   lspRange{Start: start, End: start},
+		NewText: fmt.Sprintf("%s%s rule:%s\n", indent, directive, rule),
+	}
+}
+
+func utf16Len(s string) int {
+	var n int
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
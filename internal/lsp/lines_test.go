@@ -0,0 +1,87 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPosition(t *testing.T) {
+	lines := splitLines("a: é\r\nb: 😀 x\n\uFEFF")
+
+	tests := []struct {
+		name         string
+		line, column int
+		want         position
+	}{
+		{name: "Start", line: 1, column: 1, want: position{0, 0}},
+		{name: "After Two Byte Rune", line: 1, column: 5, want: position{0, 4}},
+		{name: "Past End Of Line", line: 1, column: 9, want: position{0, 4}},
+		{name: "Surrogate Pair", line: 2, column: 5, want: position{1, 5}},
+		{name: "After Surrogate Pair", line: 2, column: 6, want: position{1, 6}},
+		{name: "Past Last Line", line: 5, column: 1, want: position{2, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, lines.position(test.line, test.column))
+		})
+	}
+
+	t.Run("Byte Order Mark", func(t *testing.T) {
+		lines := splitLines("\uFEFFa: é\n")
+		assert.Equal(t, position{0, 5}, lines.position(1, 5))
+	})
+}
+
+func TestDisableLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+		want  textEdit
+	}{
+		{
+			name:  "New Directive",
+			input: "a:\n  b: [ 1 ]\n",
+			line:  2,
+			want: textEdit{
+				Range:   lspRange{position{1, 0}, position{1, 0}},
+				NewText: "  # yamllint disable-line rule:brackets\n",
+			},
+		},
+		{
+			name:  "Directive Above",
+			input: "# yamllint disable-line rule:colons \nb: [ 1 ]\n",
+			line:  2,
+			want: textEdit{
+				Range:   lspRange{position{0, 35}, position{0, 35}},
+				NewText: " rule:brackets",
+			},
+		},
+		{
+			name:  "Directive On Line",
+			input: "b: [ 1 ] # yamllint disable-line rule:colons\n",
+			line:  1,
+			want: textEdit{
+				Range:   lspRange{position{0, 44}, position{0, 44}},
+				NewText: " rule:brackets",
+			},
+		},
+		{
+			name:  "Directive Above Other Content",
+			input: "a: 1 # yamllint disable-line rule:colons\nb: [ 1 ]\n",
+			line:  2,
+			want: textEdit{
+				Range:   lspRange{position{1, 0}, position{1, 0}},
+				NewText: "# yamllint disable-line rule:brackets\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, splitLines(test.input).disableLine(test.line, "brackets"))
+		})
+	}
+}
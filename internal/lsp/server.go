@@ -0,0 +1,418 @@
+// Package lsp implements a Language Server Protocol server that reports the
+// problems found by the linter as diagnostics while documents are edited.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/cedws/yamllintx/runner"
+)
+
+// Options configures Serve.
+type Options struct {
+	// ConfigPath is the path of the config file, relative to the root of the
+	// workspace if it is not absolute.
+	ConfigPath string
+	// Version is reported to the client.
+	Version string
+	// PollInterval is how often the config file is checked for changes.
+	PollInterval time.Duration
+}
+
+// document is an open text document and the problems last published for it.
+type document struct {
+	uri      string
+	version  int
+	text     string
+	problems []lint.Problem
+}
+
+type server struct {
+	conn *conn
+	opts Options
+
+	mu          sync.Mutex
+	initialized bool
+	shutdown    bool
+	root        string
+	configFile  *runner.ConfigFile
+	config      runner.Config
+	chain       lint.Chain
+	configErr   error
+	documents   map[string]*document
+}
+
+// Serve speaks the protocol over r and w until the client asks it to exit,
+// r is closed or ctx is cancelled.
+func Serve(ctx context.Context, r io.Reader, w io.Writer, opts Options) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+
+	s := &server{
+		conn:      newConn(r, w),
+		opts:      opts,
+		documents: make(map[string]*document),
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go s.watchConfig(ctx)
+
+	for {
+		msg, err := s.conn.read()
+
+		var rpcErr *responseError
+		switch {
+		case errors.As(err, &rpcErr):
+			if err := s.conn.reply(nil, nil, rpcErr); err != nil {
+				return err
+			}
+			continue
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		if msg.Method == "exit" {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(msg)
+
+		// Notifications have no id and get no response.
+		if msg.ID == nil {
+			continue
+		}
+
+		if err := s.conn.reply(msg.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) handle(msg *message) (any, *responseError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.initialized && msg.Method != "initialize" {
+		if msg.ID == nil {
+			return nil, nil
+		}
+		return nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.initialize(params), nil
+
+	case "initialized":
+		s.publishConfigError()
+
+	case "shutdown":
+		s.shutdown = true
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		doc := &document{
+			uri:     params.TextDocument.URI,
+			version: params.TextDocument.Version,
+			text:    params.TextDocument.Text,
+		}
+		s.documents[doc.uri] = doc
+		s.publish(doc)
+
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok || len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+
+		// The server asks for full document sync, so the last change holds
+		// the whole text.
+		doc.version = params.TextDocument.Version
+		doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.publish(doc)
+
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+		s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []diagnostic{},
+		})
+
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.codeActions(params), nil
+
+	case "workspace/didChangeWatchedFiles":
+		s.reloadConfig()
+
+	default:
+		if msg.ID != nil && !strings.HasPrefix(msg.Method, "$/") {
+			return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+		}
+	}
+
+	return nil, nil
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+func (s *server) initialize(params initializeParams) initializeResult {
+	s.initialized = true
+
+	if root, ok := uriPath(params.RootURI); ok {
+		s.root = root
+	} else if wd, err := os.Getwd(); err == nil {
+		s.root = wd
+	}
+
+	configPath := s.opts.ConfigPath
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(s.root, configPath)
+	}
+
+	s.configFile = runner.NewConfigFile(configPath)
+	s.loadConfig()
+
+	return initializeResult{
+		Capabilities: serverCapabilities{
+			PositionEncoding: "utf-16",
+			TextDocumentSync: textDocumentSync{OpenClose: true, Change: 1},
+			CodeActionProvider: codeActionProvider{
+				CodeActionKinds: []string{"quickfix"},
+			},
+		},
+		ServerInfo: serverInfo{Name: "yamllintx", Version: s.opts.Version},
+	}
+}
+
+// watchConfig polls the config file and lints the open documents again
+// whenever it changes.
+func (s *server) watchConfig(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		s.mu.Lock()
+		if s.initialized {
+			s.reloadConfig()
+		}
+		s.mu.Unlock()
+	}
+}
+
+// reloadConfig loads the config again if the file changed, and republishes
+// the diagnostics of every open document if it did.
+func (s *server) reloadConfig() {
+	reloaded := s.loadConfig()
+	s.publishConfigError()
+
+	if !reloaded {
+		return
+	}
+
+	for _, uri := range slices.Sorted(maps.Keys(s.documents)) {
+		s.publish(s.documents[uri])
+	}
+}
+
+// loadConfig reloads the config file and reports whether the config changed.
+// While the file is broken, linting goes on with the last good config.
+func (s *server) loadConfig() bool {
+	reloaded, err := s.configFile.Reload()
+	s.configErr = err
+
+	if reloaded {
+		s.config = s.configFile.Config()
+		s.chain = s.config.Chain()
+	}
+
+	return reloaded
+}
+
+func (s *server) publishConfigError() {
+	if s.configErr == nil {
+		return
+	}
+
+	s.conn.notify("window/showMessage", map[string]any{
+		"type":    1,
+		"message": fmt.Sprintf("yamllintx: failed to load config: %v", s.configErr),
+	})
+}
+
+// publish lints the document and sends its diagnostics to the client.
+func (s *server) publish(doc *document) {
+	doc.problems = nil
+	if s.selects(doc.uri) {
+		doc.problems = slices.Collect(lint.LintAll([]byte(doc.text), s.chain...))
+	}
+
+	lines := splitLines(doc.text)
+
+	diagnostics := []diagnostic{}
+	for _, problem := range doc.problems {
+		diagnostics = append(diagnostics, s.diagnostic(lines, problem))
+	}
+
+	version := doc.version
+	s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+// selects reports whether the config applies to the document. Documents that
+// are not files, or not in the workspace, are judged by their name alone.
+func (s *server) selects(uri string) bool {
+	path, ok := uriPath(uri)
+	if !ok {
+		return true
+	}
+
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+
+	selected, err := s.config.Selects(filepath.ToSlash(rel))
+	return err == nil && selected
+}
+
+func (s *server) codeActions(params codeActionParams) []codeAction {
+	actions := []codeAction{}
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return actions
+	}
+
+	lines := splitLines(doc.text)
+
+	for _, problem := range doc.problems {
+		diag := s.diagnostic(lines, problem)
+		if !overlaps(diag.Range, params.Range) {
+			continue
+		}
+
+		if len(problem.Fixes) > 0 {
+			var edits []textEdit
+			for _, edit := range problem.Fixes {
+				edits = append(edits, textEdit{
+					Range:   lines.lspRange(edit.Line, edit.Column, edit.EndLine, edit.EndColumn),
+					NewText: edit.NewText,
+				})
+			}
+
+			actions = append(actions, codeAction{
+				Title:       fmt.Sprintf("Fix %s problem", problem.Rule),
+				Kind:        "quickfix",
+				Diagnostics: []diagnostic{diag},
+				IsPreferred: true,
+				Edit:        workspaceEdit{Changes: map[string][]textEdit{doc.uri: edits}},
+			})
+		}
+
+		if problem.Rule == "syntax" {
+			continue
+		}
+
+		actions = append(actions, codeAction{
+			Title:       fmt.Sprintf("Disable %s for this line", problem.Rule),
+			Kind:        "quickfix",
+			Diagnostics: []diagnostic{diag},
+			Edit: workspaceEdit{Changes: map[string][]textEdit{
+				doc.uri: {lines.disableLine(problem.Line, problem.Rule)},
+			}},
+		})
+	}
+
+	return actions
+}
+
+// diagnostic converts a problem to a diagnostic, with the severity of the
+// level of its rule.
+func (s *server) diagnostic(lines documentLines, problem lint.Problem) diagnostic {
+	severity := severityError
+	if s.config.Level(problem.Rule) == runner.LevelWarning {
+		severity = severityWarning
+	}
+
+	return diagnostic{
+		Range:    lines.lspRange(problem.Line, problem.Column, problem.EndLine, problem.EndColumn),
+		Severity: severity,
+		Code:     problem.Rule,
+		Source:   "yamllintx",
+		Message:  problem.Message(),
+	}
+}
+
+func overlaps(a, b lspRange) bool {
+	return !before(a.End, b.Start) && !before(b.End, a.Start)
+}
+
+func before(a, b position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// uriPath returns the file system path of a file URI.
+func uriPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
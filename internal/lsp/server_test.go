@@ -0,0 +1,165 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient drives a server over pipes, as an editor would.
+type testClient struct {
+	t      *testing.T
+	conn   *conn
+	nextID int
+	done   chan error
+}
+
+func newTestClient(t *testing.T, opts Options) *testClient {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &testClient{
+		t:    t,
+		conn: newConn(clientR, clientW),
+		done: make(chan error, 1),
+	}
+
+	go func() {
+		c.done <- Serve(context.Background(), serverR, serverW, opts)
+		serverW.Close()
+	}()
+
+	t.Cleanup(func() { clientW.Close() })
+
+	return c
+}
+
+func (c *testClient) notify(method string, params any) {
+	require.NoError(c.t, c.conn.notify(method, params))
+}
+
+// request sends a request and returns its result, collecting the
+// notifications that arrive before it.
+func (c *testClient) request(method string, params any, result any) []*message {
+	c.nextID++
+	id := json.RawMessage(fmt.Sprint(c.nextID))
+
+	raw, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.conn.write(&message{ID: &id, Method: method, Params: raw}))
+
+	var notifications []*message
+	for {
+		msg, err := c.conn.read()
+		require.NoError(c.t, err)
+
+		if msg.ID == nil {
+			notifications = append(notifications, msg)
+			continue
+		}
+
+		require.Nil(c.t, msg.Error)
+		if result != nil {
+			bytes, err := json.Marshal(msg.Result)
+			require.NoError(c.t, err)
+			require.NoError(c.t, json.Unmarshal(bytes, result))
+		}
+		return notifications
+	}
+}
+
+// diagnostics waits for the next diagnostics published for uri.
+func (c *testClient) diagnostics(uri string) []diagnostic {
+	for {
+		msg, err := c.conn.read()
+		require.NoError(c.t, err)
+
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params publishDiagnosticsParams
+		require.NoError(c.t, json.Unmarshal(msg.Params, &params))
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+}
+
+func fileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("rules:\n  brackets:\n    level: warning\n"), 0o644))
+
+	c := newTestClient(t, Options{ConfigPath: "config.yml", PollInterval: 10 * time.Millisecond})
+
+	var init initializeResult
+	c.request("initialize", initializeParams{RootURI: fileURI(dir)}, &init)
+	assert.Equal(t, "utf-16", init.Capabilities.PositionEncoding)
+	c.notify("initialized", struct{}{})
+
+	uri := fileURI(filepath.Join(dir, "values.yaml"))
+
+	c.notify("textDocument/didOpen", didOpenParams{
+		TextDocument: textDocumentItem{URI: uri, Version: 1, Text: "emoji: 😀\nlist: [ a ]\n"},
+	})
+
+	diagnostics := c.diagnostics(uri)
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, "brackets", diagnostics[0].Code)
+	assert.Equal(t, severityWarning, diagnostics[0].Severity)
+	assert.Equal(t, lspRange{position{1, 7}, position{1, 8}}, diagnostics[0].Range)
+	assert.Equal(t, "too many spaces inside brackets", diagnostics[0].Message)
+
+	t.Run("Change", func(t *testing.T) {
+		c.notify("textDocument/didChange", didChangeParams{
+			TextDocument: textDocumentItem{URI: uri, Version: 2},
+			ContentChanges: []struct {
+				Text string `json:"text"`
+			}{{Text: "list: [😀 ]\n"}},
+		})
+
+		diagnostics := c.diagnostics(uri)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, lspRange{position{0, 9}, position{0, 10}}, diagnostics[0].Range)
+	})
+
+	t.Run("Code Actions", func(t *testing.T) {
+		var actions []codeAction
+		c.request("textDocument/codeAction", codeActionParams{
+			TextDocument: textDocumentIdentifier{URI: uri},
+			Range:        lspRange{position{0, 0}, position{0, 12}},
+		}, &actions)
+
+		require.Len(t, actions, 2)
+		assert.Equal(t, []textEdit{{Range: lspRange{position{0, 9}, position{0, 10}}, NewText: ""}}, actions[0].Edit.Changes[uri])
+		assert.Equal(t, "# yamllint disable-line rule:brackets\n", actions[1].Edit.Changes[uri][0].NewText)
+	})
+
+	t.Run("Config Reload", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configPath, []byte("rules: {}\n"), 0o644))
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(configPath, later, later))
+
+		assert.Empty(t, c.diagnostics(uri))
+	})
+
+	t.Run("Shutdown", func(t *testing.T) {
+		c.request("shutdown", nil, nil)
+		c.notify("exit", nil)
+		assert.NoError(t, <-c.done)
+	})
+}
@@ -0,0 +1,127 @@
+package pretty
+
+import "strings"
+
+// lineLayout maps the runes of a line to the terminal columns they are drawn
+// at, with tabs expanded to spaces.
+type lineLayout struct {
+	// cells holds how each rune is drawn.
+	cells []string
+	// starts holds the column each rune starts at, followed by the width
+	// of the whole line.
+	starts []int
+}
+
+func layout(text string) lineLayout {
+	var l lineLayout
+	col := 0
+
+	for _, r := range text {
+		l.starts = append(l.starts, col)
+
+		if r == '\t' {
+			width := tabWidth - col%tabWidth
+			l.cells = append(l.cells, strings.Repeat(" ", width))
+			col += width
+			continue
+		}
+
+		width := runeWidth(r)
+		if width == 0 && (r < 0x20 || r == 0x7F) {
+			// Draw control characters visibly instead of letting them
+			// move the cursor.
+			l.cells = append(l.cells, "�")
+			col++
+			continue
+		}
+
+		l.cells = append(l.cells, string(r))
+		col += width
+	}
+
+	l.starts = append(l.starts, col)
+	return l
+}
+
+// column returns the terminal column of the 1-based rune column. Columns
+// past the end of the line continue one column per rune.
+func (l lineLayout) column(column int) int {
+	i := max(column-1, 0)
+	if i < len(l.starts) {
+		return l.starts[i]
+	}
+	return l.end() + i - (len(l.starts) - 1)
+}
+
+func (l lineLayout) end() int {
+	return l.starts[len(l.starts)-1]
+}
+
+// indent returns the column of the first rune that is not a space or tab.
+func (l lineLayout) indent() int {
+	for i, cell := range l.cells {
+		if strings.TrimSpace(cell) != "" {
+			return l.starts[i]
+		}
+	}
+	return l.end()
+}
+
+// shownLine is the part of a line that fits the available width.
+type shownLine struct {
+	text string
+	// offset is the column of the line drawn first, minus the columns
+	// taken by the marker of a cut start.
+	offset int
+	width  int
+}
+
+// window returns the part of the line that fits in width columns, keeping
+// focus visible. Cut ends are marked with an ellipsis. A width of zero means
+// no limit.
+func (l lineLayout) window(width, focus int) shownLine {
+	total := l.end()
+	if width <= 0 || total <= width {
+		return shownLine{text: strings.Join(l.cells, ""), width: total}
+	}
+
+	// Leave some of the line before the focus visible, for context. Each
+	// cut end takes a column for its marker.
+	from := focus - width/4
+	var to int
+	switch {
+	case from <= 0:
+		from, to = 0, width-1
+	case total-from <= width-1:
+		from, to = total-(width-1), total
+	default:
+		to = from + width - 2
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+
+	for i, cell := range l.cells {
+		if l.starts[i] >= from && l.starts[i+1] <= to {
+			b.WriteString(cell)
+		}
+	}
+
+	if to < total {
+		b.WriteString("…")
+	}
+
+	offset := 0
+	if from > 0 {
+		offset = from - 1
+	}
+
+	return shownLine{text: b.String(), offset: offset, width: to - offset}
+}
+
+// clip converts a column of the line to a column of the shown part.
+func (s shownLine) clip(column int) int {
+	return min(max(column-s.offset, 0), s.width)
+}
@@ -0,0 +1,327 @@
+// Package pretty renders problems for people to read, with the source they
+// are in and the range they cover underlined, in the style of rustc.
+package pretty
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/cedws/yamllintx/runner"
+)
+
+const utf8BOM = "\uFEFF"
+
+// ANSI escape sequences for the parts of the output that are coloured.
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleError   = "\x1b[1;31m"
+	styleWarning = "\x1b[1;33m"
+	styleGutter  = "\x1b[1;34m"
+	styleHelp    = "\x1b[1;36m"
+)
+
+// maxRangeLines is the number of lines of a problem shown in full. Longer
+// ranges show their first and last lines only.
+const maxRangeLines = 4
+
+// Printer renders the problems of files.
+type Printer struct {
+	// Color enables ANSI colours.
+	Color bool
+	// Width is the number of columns available. Source lines that do not
+	// fit are cut around the problem. Zero means no limit.
+	Width int
+	// Context is the number of lines shown before and after a problem.
+	Context int
+	// Level returns the level of the problems of a rule. All problems are
+	// errors if it is nil.
+	Level func(rule string) runner.Level
+}
+
+// ForTerminal returns a printer for output to f. It is coloured if f is a
+// terminal and NO_COLOR is not set, and as wide as the terminal, or as
+// $COLUMNS if the terminal does not say.
+func ForTerminal(f *os.File) Printer {
+	p := Printer{Context: 1}
+
+	_, noColor := os.LookupEnv("NO_COLOR")
+	width, terminal := terminalWidth(f)
+
+	p.Color = terminal && !noColor
+	p.Width = width
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 && !terminal {
+		p.Width = columns
+	}
+
+	return p
+}
+
+// Print writes the problems of the result.
+func (p Printer) Print(w io.Writer, result runner.FileResult) error {
+	bw := bufio.NewWriter(w)
+	src := newSource(result.Source)
+
+	for _, problem := range result.Problems {
+		p.printProblem(bw, result.Path, src, problem)
+	}
+
+	return bw.Flush()
+}
+
+func (p Printer) style(style, s string) string {
+	if !p.Color {
+		return s
+	}
+	return style + s + styleReset
+}
+
+func (p Printer) printProblem(w *bufio.Writer, path string, src source, problem lint.Problem) {
+	level := runner.LevelError
+	if p.Level != nil {
+		level = p.Level(problem.Rule)
+	}
+
+	levelStyle := styleError
+	if level == runner.LevelWarning {
+		levelStyle = styleWarning
+	}
+
+	fmt.Fprintf(w, "%s%s\n",
+		p.style(levelStyle, fmt.Sprintf("%s[%s]", level, problem.Rule)),
+		p.style(styleBold, ": "+problem.Message()),
+	)
+
+	lines := p.shownLines(src, problem)
+
+	gutter := 1
+	if len(lines) > 0 {
+		gutter = len(strconv.Itoa(lines[len(lines)-1]))
+	}
+	pad := strings.Repeat(" ", gutter)
+
+	fmt.Fprintf(w, "%s%s %s:%d:%d\n", pad, p.style(styleGutter, "-->"), path, problem.Line, problem.Column)
+
+	if len(lines) == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+
+	bar := p.style(styleGutter, "|")
+	fmt.Fprintf(w, "%s %s\n", pad, bar)
+
+	first, last := rangeLines(src, problem)
+
+	for i, line := range lines {
+		if i > 0 && line != lines[i-1]+1 {
+			fmt.Fprintf(w, "%s\n", p.style(styleGutter, "..."))
+		}
+
+		l := layout(src.line(line))
+
+		// Underline the part of the line the problem covers.
+		var from, to int
+		underline := line >= first && line <= last
+		if underline {
+			from, to = l.indent(), l.end()
+			if line == first {
+				from = l.column(problem.Column)
+			}
+			if line == last {
+				to = l.column(problem.EndColumn)
+			}
+			if line == problem.EndLine && problem.EndColumn == 1 && line != first {
+				underline = false
+			}
+		}
+
+		window := l.window(p.available(gutter), from)
+
+		fmt.Fprintf(w, "%s %s %s\n", p.style(styleGutter, fmt.Sprintf("%*d", gutter, line)), bar, window.text)
+
+		if !underline {
+			continue
+		}
+
+		start, end := window.clip(from), window.clip(to)
+		marks := strings.Repeat("~", max(end-start, 1))
+		if line == first {
+			marks = "^" + marks[1:]
+		}
+
+		fmt.Fprintf(w, "%s %s %s%s\n", pad, bar, strings.Repeat(" ", start), p.style(levelStyle, marks))
+	}
+
+	fmt.Fprintf(w, "%s %s\n", pad, bar)
+
+	if len(problem.Fixes) > 0 {
+		p.printFix(w, src, problem, gutter)
+	}
+
+	fmt.Fprintln(w)
+}
+
+// printFix shows the lines touched by the fixes of the problem as they are
+// after the fixes are applied.
+func (p Printer) printFix(w *bufio.Writer, src source, problem lint.Problem, gutter int) {
+	edits := slices.SortedFunc(slices.Values(problem.Fixes), func(a, b lint.Edit) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+
+	firstLine, lastLine := edits[0].Line, edits[0].EndLine
+	start, end := src.lineStart(firstLine), src.lineEnd(lastLine)
+	for _, edit := range edits {
+		lastLine = max(lastLine, edit.EndLine)
+		end = max(end, src.lineEnd(edit.EndLine), edit.EndOffset)
+	}
+
+	var fixed strings.Builder
+	at := start
+	for _, edit := range edits {
+		if edit.Offset < at || edit.EndOffset > len(src.bytes) {
+			return
+		}
+		fixed.Write(src.bytes[at:edit.Offset])
+		fixed.WriteString(edit.NewText)
+		at = edit.EndOffset
+	}
+	fixed.Write(src.bytes[at:end])
+
+	pad := strings.Repeat(" ", gutter)
+	bar := p.style(styleGutter, "|")
+
+	fmt.Fprintf(w, "%s %s %s\n", pad, p.style(styleGutter, "="), p.style(styleHelp, "help: apply the suggested fix"))
+	fmt.Fprintf(w, "%s %s\n", pad, bar)
+
+	for i, text := range strings.Split(fixed.String(), "\n") {
+		text = strings.TrimSuffix(text, "\r")
+		if i == 0 && firstLine == 1 {
+			text = strings.TrimPrefix(text, utf8BOM)
+		}
+
+		l := layout(text)
+		window := l.window(p.available(gutter), 0)
+		fmt.Fprintf(w, "%s %s %s\n", p.style(styleGutter, fmt.Sprintf("%*d", gutter, firstLine+i)), bar, window.text)
+	}
+
+	fmt.Fprintf(w, "%s %s\n", pad, bar)
+}
+
+// available returns the number of columns left for source text after the
+// gutter, or zero for no limit.
+func (p Printer) available(gutter int) int {
+	if p.Width <= 0 {
+		return 0
+	}
+	return max(p.Width-gutter-3, 10)
+}
+
+// shownLines returns the numbers of the source lines shown for the problem.
+func (p Printer) shownLines(src source, problem lint.Problem) []int {
+	first, last := rangeLines(src, problem)
+	if first > src.len() || first < 1 {
+		return nil
+	}
+
+	var lines []int
+	add := func(from, to int) {
+		for line := max(from, 1); line <= min(to, src.len()); line++ {
+			if len(lines) == 0 || line > lines[len(lines)-1] {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	if last-first+1 > maxRangeLines {
+		add(first-p.Context, first+1)
+		add(last-1, last+p.Context)
+	} else {
+		add(first-p.Context, last+p.Context)
+	}
+
+	return lines
+}
+
+// rangeLines returns the first and last line the problem covers. A range
+// that ends at the start of a line does not cover that line.
+func rangeLines(src source, problem lint.Problem) (int, int) {
+	last := max(problem.EndLine, problem.Line)
+	if last > problem.Line && problem.EndColumn == 1 {
+		last--
+	}
+	return problem.Line, min(last, src.len())
+}
+
+// source gives access to the lines of a file.
+type source struct {
+	bytes []byte
+	// starts holds the byte offset at which each line starts.
+	starts []int
+	bom    bool
+}
+
+func newSource(src []byte) source {
+	s := source{bytes: src, starts: []int{0}, bom: bytes.HasPrefix(src, []byte(utf8BOM))}
+	for i, b := range src {
+		if b == '\n' {
+			s.starts = append(s.starts, i+1)
+		}
+	}
+
+	// A terminated last line is not followed by another line.
+	if len(src) > 0 && src[len(src)-1] == '\n' {
+		s.starts = s.starts[:len(s.starts)-1]
+	}
+	if len(src) == 0 {
+		s.starts = nil
+	}
+
+	return s
+}
+
+func (s source) len() int {
+	return len(s.starts)
+}
+
+func (s source) lineStart(line int) int {
+	line = min(max(line, 1), len(s.starts))
+	if line == 0 {
+		return 0
+	}
+	return s.starts[line-1]
+}
+
+// lineEnd returns the offset of the end of the line, before its terminator.
+func (s source) lineEnd(line int) int {
+	start := s.lineStart(line)
+
+	end := len(s.bytes)
+	if line < len(s.starts) {
+		end = s.starts[line]
+	}
+
+	text := s.bytes[start:end]
+	text = bytes.TrimSuffix(text, []byte("\n"))
+	text = bytes.TrimSuffix(text, []byte("\r"))
+
+	return start + len(text)
+}
+
+// line returns the text of the 1-based line, without its terminator and,
+// for the first line, without the byte order mark.
+func (s source) line(line int) string {
+	text := string(s.bytes[s.lineStart(line):s.lineEnd(line)])
+	if line == 1 && s.bom {
+		text = strings.TrimPrefix(text, utf8BOM)
+	}
+	return text
+}
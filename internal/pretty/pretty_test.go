@@ -0,0 +1,157 @@
+package pretty
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/cedws/yamllintx/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, p Printer, src string, linters ...lint.Linter) string {
+	t.Helper()
+
+	result := runner.FileResult{
+		Path:     "values.yaml",
+		Source:   []byte(src),
+		Problems: slices.Collect(lint.LintAll([]byte(src), linters...)),
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Print(&buf, result))
+	return buf.String()
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		name    string
+		printer Printer
+		input   string
+		want    string
+	}{
+		{
+			name:    "Context And Fix",
+			printer: Printer{Context: 1},
+			input:   "a: 1\nb: 2   \nc: 3\nd: 4\n",
+			want: `error[trailing-spaces]: trailing spaces are forbidden
+ --> values.yaml:2:5
+  |
+1 | a: 1
+2 | b: 2   
+  |     ^~~
+3 | c: 3
+  |
+  = help: apply the suggested fix
+  |
+2 | b: 2
+  |
+
+`,
+		},
+		{
+			name:    "Tabs And Wide Runes",
+			printer: Printer{},
+			input:   "k: \"\t日本😀\"   \n",
+			want: `error[trailing-spaces]: trailing spaces are forbidden
+ --> values.yaml:1:10
+  |
+1 | k: "    日本😀"   
+  |                ^~~
+  |
+  = help: apply the suggested fix
+  |
+1 | k: "    日本😀"
+  |
+
+`,
+		},
+		{
+			name: "Warning",
+			printer: Printer{Level: func(string) runner.Level {
+				return runner.LevelWarning
+			}},
+			input: "a: 1 \n",
+			want: `warning[trailing-spaces]: trailing spaces are forbidden
+ --> values.yaml:1:5
+  |
+1 | a: 1 
+  |     ^
+  |
+  = help: apply the suggested fix
+  |
+1 | a: 1
+  |
+
+`,
+		},
+		{
+			name:    "Narrow Terminal",
+			printer: Printer{Width: 24},
+			input:   "key: value that goes on and on and on   \n",
+			want: `error[trailing-spaces]: trailing spaces are forbidden
+ --> values.yaml:1:38
+  |
+1 | …on and on and on   
+  |                  ^~~
+  |
+  = help: apply the suggested fix
+  |
+1 | key: value that goe…
+  |
+
+`,
+		},
+		{
+			name:    "Multiple Lines",
+			printer: Printer{},
+			input:   "a: {\n  b: 1,\n  c: 2,\n  d: 3,\n  e: 4,\n  f: 5}\n",
+			want: `error[braces]: braces are forbidden
+ --> values.yaml:1:4
+  |
+1 | a: {
+  |    ^
+2 |   b: 1,
+  |   ~~~~~
+...
+5 |   e: 4,
+  |   ~~~~~
+6 |   f: 5}
+  |   ~~~~~
+  |
+
+`,
+		},
+		{
+			name:    "Color",
+			printer: Printer{Color: true},
+			input:   "a: 010\n",
+			want: "\x1b[1;31merror[octal]\x1b[0m\x1b[1m: implicit octal literals are forbidden\x1b[0m\n" +
+				" \x1b[1;34m-->\x1b[0m values.yaml:1:4\n" +
+				"  \x1b[1;34m|\x1b[0m\n" +
+				"\x1b[1;34m1\x1b[0m \x1b[1;34m|\x1b[0m a: 010\n" +
+				"  \x1b[1;34m|\x1b[0m    \x1b[1;31m^~~\x1b[0m\n" +
+				"  \x1b[1;34m|\x1b[0m\n\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			linters := []lint.Linter{
+				lint.TrailingSpaces{},
+				lint.Braces{Forbid: lint.ForbidBracesAll},
+				lint.Octal{ForbidImplicitOctal: true},
+			}
+			assert.Equal(t, test.want, render(t, test.printer, test.input, linters...))
+		})
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	assert.Equal(t, 1, runeWidth('a'))
+	assert.Equal(t, 2, runeWidth('日'))
+	assert.Equal(t, 2, runeWidth('😀'))
+	assert.Equal(t, 0, runeWidth('\u0301'))
+	assert.Equal(t, 0, runeWidth('\u200b'))
+}
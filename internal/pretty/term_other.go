@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package pretty
+
+import "os"
+
+func terminalWidth(f *os.File) (int, bool) {
+	return 0, false
+}
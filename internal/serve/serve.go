@@ -0,0 +1,145 @@
+// Package serve implements an HTTP service that lints the YAML posted to it
+// with the rules of a config file.
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/cedws/yamllintx/runner"
+)
+
+// Options configures the handler.
+type Options struct {
+	// MaxBodyBytes limits the size of the YAML accepted in a request. Zero
+	// means no limit.
+	MaxBodyBytes int64
+	// Timeout limits the time spent linting a request. Zero means no limit.
+	Timeout time.Duration
+}
+
+// Response is the JSON body returned for a linted document.
+type Response struct {
+	// Selected is false if the config does not apply to the filename given
+	// in the request, in which case the document was not linted.
+	Selected bool      `json:"selected"`
+	Problems []Problem `json:"problems"`
+}
+
+// Problem is a lint.Problem as it is encoded in a Response.
+type Problem struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+	Fixable   bool   `json:"fixable"`
+}
+
+// statusClientClosedRequest is the status of a request that the client
+// cancelled before it was answered, as nginx logs it.
+const statusClientClosedRequest = 499
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the handler of the service. It lints the body of POST
+// requests to /lint, applying the config to the path given in the filename
+// query parameter if there is one, and answers GET /healthz.
+func Handler(config *runner.ConfigFile, opts Options) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.HandleFunc("POST /lint", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+		}
+
+		body := r.Body
+		if opts.MaxBodyBytes > 0 {
+			body = http.MaxBytesReader(w, body, opts.MaxBodyBytes)
+		}
+
+		src, err := io.ReadAll(body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{
+					Error: fmt.Sprintf("request body is larger than %d bytes", maxBytesErr.Limit),
+				})
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+
+		cfg := config.Config()
+
+		response := Response{Selected: true, Problems: []Problem{}}
+
+		if filename := r.URL.Query().Get("filename"); filename != "" {
+			selected, err := cfg.Selects(path.Clean(filename))
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+				return
+			}
+			if !selected {
+				writeJSON(w, http.StatusOK, Response{Problems: []Problem{}})
+				return
+			}
+		}
+
+		problems, err := runner.LintSource(ctx, src, cfg.Chain())
+		switch {
+		case opts.Timeout > 0 && errors.Is(err, context.DeadlineExceeded):
+			writeJSON(w, http.StatusServiceUnavailable, errorResponse{
+				Error: fmt.Sprintf("linting took longer than %s", opts.Timeout),
+			})
+			return
+		case err != nil:
+			// The client has gone away, so the response is only for the logs.
+			writeJSON(w, statusClientClosedRequest, errorResponse{Error: err.Error()})
+			return
+		}
+
+		for _, problem := range problems {
+			response.Problems = append(response.Problems, Problem{
+				Line:      problem.Line,
+				Column:    problem.Column,
+				EndLine:   problem.EndLine,
+				EndColumn: problem.EndColumn,
+				Rule:      problem.Rule,
+				Message:   problem.Message(),
+				Fixable:   len(problem.Fixes) > 0,
+			})
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cedws/yamllintx/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("ignore: ['vendor/**']\nrules:\n  brackets: {}\n  trailing-spaces: {}\n"), 0o644))
+
+	config, err := runner.OpenConfigFile(path)
+	require.NoError(t, err)
+
+	handler := Handler(config, Options{MaxBodyBytes: 64, Timeout: time.Minute})
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("Health", func(t *testing.T) {
+		rec := do(http.MethodGet, "/healthz", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Lint", func(t *testing.T) {
+		rec := do(http.MethodPost, "/lint", "list: [ a ] \n")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		assert.True(t, response.Selected)
+		assert.Equal(t, Problem{
+			Line:      1,
+			Column:    12,
+			EndLine:   1,
+			EndColumn: 13,
+			Rule:      "trailing-spaces",
+			Message:   "trailing spaces are forbidden",
+			Fixable:   true,
+		}, response.Problems[2])
+		assert.Len(t, response.Problems, 3)
+	})
+
+	t.Run("Ignored Filename", func(t *testing.T) {
+		rec := do(http.MethodPost, "/lint?filename=vendor/values.yaml", "list: [ a ] \n")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"selected": false, "problems": []}`, rec.Body.String())
+	})
+
+	t.Run("Selected Filename", func(t *testing.T) {
+		rec := do(http.MethodPost, "/lint?filename=charts/values.yaml", "a: 1\n")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"selected": true, "problems": []}`, rec.Body.String())
+	})
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		rec := do(http.MethodPost, "/lint", strings.Repeat("a: 1\n", 20))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("Method Not Allowed", func(t *testing.T) {
+		rec := do(http.MethodGet, "/lint", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		handler := Handler(config, Options{Timeout: time.Nanosecond})
+
+		for _, body := range []string{"list: [ a ]\n", "list: [a]\n"} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/lint", strings.NewReader(body)))
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code, body)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/lint", strings.NewReader("list: [a]\n")).WithContext(ctx)
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, statusClientClosedRequest, rec.Code)
+
+		// Without a time limit, there is no timeout to blame.
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPost, "/lint", strings.NewReader("list: [a]\n")).WithContext(ctx)
+		Handler(config, Options{}).ServeHTTP(rec, req)
+		assert.Equal(t, statusClientClosedRequest, rec.Code)
+	})
+}
@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+// DisableLineDirective starts a comment that suppresses problems on its line,
+// or on the next line if the comment is on a line of its own. It can be
+// followed by rule:<name> for each rule to suppress, and suppresses every
+// rule otherwise.
+const DisableLineDirective = "yamllint disable-line"
+
+// disabledRules lists the rules suppressed on a line. A nil list suppresses
+// every rule.
+type disabledRules struct {
+	rules []string
+}
+
+type disabledLines map[int]disabledRules
+
+func findDisabledLines(tokens token.Tokens) disabledLines {
+	disabled := make(disabledLines)
+
+	for i, tk := range tokens {
+		if tk.Type != token.CommentType {
+			continue
+		}
+
+		rules, ok := parseDisableLine(tk.Value)
+		if !ok {
+			continue
+		}
+
+		line := tk.Position.Line
+		disabled.add(line, rules)
+
+		if i == 0 || tokens[i-1].Position.Line != line {
+			disabled.add(line+1, rules)
+		}
+	}
+
+	return disabled
+}
+
+// parseDisableLine parses the text of a comment, without the leading #, as a
+// disable-line directive.
+func parseDisableLine(comment string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(comment), DisableLineDirective)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, false
+	}
+
+	rules := []string{}
+	for _, field := range strings.Fields(rest) {
+		if rule, ok := strings.CutPrefix(field, "rule:"); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, true
+	}
+
+	return rules, true
+}
+
+func (d disabledLines) add(line int, rules []string) {
+	current, ok := d[line]
+
+	switch {
+	case !ok:
+		d[line] = disabledRules{rules: rules}
+	case current.rules == nil || rules == nil:
+		d[line] = disabledRules{}
+	default:
+		d[line] = disabledRules{rules: append(slices.Clip(current.rules), rules...)}
+	}
+}
+
+func (d disabledLines) disables(problem Problem) bool {
+	disabled, ok := d[problem.Line]
+	if !ok {
+		return false
+	}
+
+	return disabled.rules == nil || slices.Contains(disabled.rules, problem.Rule)
+}
@@ -0,0 +1,67 @@
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisableLine(t *testing.T) {
+	linters := []Linter{
+		TrailingSpaces{},
+		Brackets{},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "No Directive",
+			input: "a: [ 1 ] \n",
+			want:  []string{"1:5 brackets", "1:7 brackets", "1:9 trailing-spaces"},
+		},
+		{
+			name:  "All Rules",
+			input: "a: [ 1 ] # yamllint disable-line\nb: [ 2 ]\n",
+			want:  []string{"2:5 brackets", "2:7 brackets"},
+		},
+		{
+			name:  "One Rule",
+			input: "a: [ 1 ] # yamllint disable-line rule:brackets \n",
+			want:  []string{"1:47 trailing-spaces"},
+		},
+		{
+			name:  "Several Rules",
+			input: "a: [ 1 ] # yamllint disable-line rule:brackets rule:trailing-spaces \n",
+		},
+		{
+			name:  "Line Above",
+			input: "# yamllint disable-line rule:brackets\na: [ 1 ] \nb: [ 2 ]\n",
+			want:  []string{"2:9 trailing-spaces", "3:5 brackets", "3:7 brackets"},
+		},
+		{
+			name:  "Other Comment",
+			input: "a: [ 1 ] # yamllint disable-lines\n",
+			want:  []string{"1:5 brackets", "1:7 brackets"},
+		},
+		{
+			name:  "Inside Block Scalar",
+			input: "a: |\n  # yamllint disable-line\nb: [ 1 ]\n",
+			want:  []string{"3:5 brackets", "3:7 brackets"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for problem := range LintAll([]byte(test.input), linters...) {
+				got = append(got, fmt.Sprintf("%d:%d %s", problem.Line, problem.Column, problem.Rule))
+			}
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -1,36 +1,133 @@
 package lint
 
 import (
-	"bufio"
 	"bytes"
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"iter"
+	"math"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
 	"github.com/goccy/go-yaml/lexer"
+	"github.com/goccy/go-yaml/parser"
 	"github.com/goccy/go-yaml/token"
 )
 
 var lintError = errors.New("lint error")
 
+// ErrSyntax is reported, under the rule id "syntax", for source that is not
+// valid YAML.
+var ErrSyntax = newError("syntax error")
+
+// ErrTimeout is reported, under the rule id "timeout", for source that could
+// not be linted within a time budget.
+var ErrTimeout = newError("linting did not finish within")
+
+// TimeoutProblem returns the problem reported for the source when linting it
+// did not finish within the timeout.
+func TimeoutProblem(src []byte, timeout time.Duration) Problem {
+	problem := problem(1, 1, fmt.Errorf("%w %s", ErrTimeout, timeout))
+	problem.Rule = "timeout"
+	return newSourceIndex(src).resolve(problem)
+}
+
+// ruleErrors holds every error created with newError, so that the error of
+// a problem can be found again from its message.
+var ruleErrors []error
+
+// newError creates an error reported by a rule.
+func newError(text string) error {
+	err := errors.New(text)
+	ruleErrors = append(ruleErrors, err)
+	return err
+}
+
 func newLintError(err error) error {
 	return fmt.Errorf("%w: %w", lintError, err)
 }
 
-func problem(lint, column int, err error) Problem {
+// messageError is an error rebuilt from its message, wrapping the rule error
+// the message starts with.
+type messageError struct {
+	message string
+	err     error
+}
+
+func (e *messageError) Error() string { return e.message }
+func (e *messageError) Unwrap() error { return e.err }
+
+// ErrorFromMessage rebuilds the error of a problem from its Message, for
+// problems read back from storage. It wraps the rule error the message
+// starts with, so that errors.Is and Problem.Message work as they do on the
+// original problem.
+func ErrorFromMessage(message string) error {
+	var match error
+
+	for _, err := range ruleErrors {
+		text := err.Error()
+		rest, ok := strings.CutPrefix(message, text)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != ':') {
+			continue
+		}
+		if match == nil || len(text) > len(match.Error()) {
+			match = err
+		}
+	}
+
+	if match == nil {
+		return newLintError(errors.New(message))
+	}
+	if match.Error() == message {
+		return newLintError(match)
+	}
+	return newLintError(&messageError{message: message, err: match})
+}
+
+func problem(line, column int, err error) Problem {
+	return problemRange(line, column, line, column+1, err)
+}
+
+func problemRange(line, column, endLine, endColumn int, err error) Problem {
 	return Problem{
-		Line:   lint,
-		Column: column,
-		Error:  newLintError(err),
+		Line:      line,
+		Column:    column,
+		EndLine:   endLine,
+		EndColumn: endColumn,
+		Error:     newLintError(err),
 	}
 }
 
-func leadingSpaces(s string) int {
-	if len(s) == 0 {
-		return 0
+func replace(line, column, endLine, endColumn int, newText string) Edit {
+	return Edit{
+		Line:      line,
+		Column:    column,
+		EndLine:   endLine,
+		EndColumn: endColumn,
+		NewText:   newText,
 	}
-	return len(s) - len(strings.TrimLeft(s, " "))
+}
+
+func insert(line, column int, text string) Edit {
+	return replace(line, column, line, column, text)
+}
+
+// respace replaces the run of spaces that starts just after the token with the
+// wanted number of spaces.
+func respace(tk *token.Token, spaces, want int) Edit {
+	line, column := tokenEnd(tk)
+	return replace(line, column, line, column+spaces, strings.Repeat(" ", max(want, 0)))
+}
+
+func tokenProblem(tk *token.Token, err error) Problem {
+	return spanProblem(tk, tk, err)
 }
 
 func trailingSpaces(s string) int {
@@ -40,6 +137,176 @@ func trailingSpaces(s string) int {
 	return len(s) - len(strings.TrimRight(s, " "))
 }
 
+// spacesProblem covers the run of spaces that starts just after the token.
+func spacesProblem(tk *token.Token, spaces int, err error) Problem {
+	line, column := tokenEnd(tk)
+	return problemRange(line, column, line, column+spaces, err)
+}
+
+// spanProblem covers everything from the start of one token to the end of
+// another.
+func spanProblem(start, end *token.Token, err error) Problem {
+	line, column := tokenStart(start)
+	endLine, endColumn := tokenEnd(end)
+	return problemRange(line, column, endLine, endColumn, err)
+}
+
+// flowEnd returns the token that closes the flow collection opened by start,
+// or start itself if the collection is never closed.
+func flowEnd(start *token.Token, endType token.Type) *token.Token {
+	depth := 0
+
+	for tk := start; tk != nil; tk = tk.Next {
+		switch tk.Type {
+		case start.Type:
+			depth++
+		case endType:
+			depth--
+			if depth == 0 {
+				return tk
+			}
+		}
+	}
+
+	return start
+}
+
+// tokenStart returns the position of the first character of the token as it
+// appears in the source. The lexer drops the whitespace after a plain scalar
+// that ends its line and shifts the scalar's column right by the same amount,
+// so for those tokens the column is worked out from the whitespace in Origin
+// and the end of the previous token instead.
+func tokenStart(tk *token.Token) (int, int) {
+	line, column := tk.Position.Line, tk.Position.Column
+	if !isPlainScalar(tk) || (tk.Next != nil && tk.Next.Position.Line == line) {
+		return line, column
+	}
+
+	blank := tk.Origin[:len(tk.Origin)-len(strings.TrimLeft(tk.Origin, " \t\r\n"))]
+	if i := strings.LastIndexByte(blank, '\n'); i >= 0 {
+		return line, len(blank) - i
+	}
+
+	if tk.Prev == nil {
+		return line, len(blank) + 1
+	}
+
+	prevLine, prevColumn := tokenEnd(tk.Prev)
+	if prevLine != line {
+		return line, len(blank) + 1
+	}
+
+	return line, prevColumn + trailingBlanks(tk.Prev.Origin) + len(blank)
+}
+
+func isPlainScalar(tk *token.Token) bool {
+	switch tk.Type {
+	case token.StringType, token.NullType, token.BoolType, token.InfinityType, token.NanType,
+		token.IntegerType, token.BinaryIntegerType, token.OctetIntegerType, token.HexIntegerType,
+		token.FloatType:
+		return true
+	}
+	return false
+}
+
+// trailingBlanks counts the spaces and tabs at the end of s.
+func trailingBlanks(s string) int {
+	return len(s) - len(strings.TrimRight(s, " \t"))
+}
+
+// tokenEnd returns the position just past the last character of the token as
+// it appears in the source.
+func tokenEnd(tk *token.Token) (int, int) {
+	line, column := tokenStart(tk)
+
+	text := strings.TrimLeft(tk.Origin, " \t\r\n")
+	text = strings.TrimRight(text, " \t\r\n")
+
+	for _, r := range text {
+		if r == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+
+	return line, column
+}
+
+// spacesBetween returns the number of columns between the end of one token
+// and the start of the next, or -1 if they are not on the same line.
+func spacesBetween(before, after *token.Token) int {
+	endLine, endColumn := tokenEnd(before)
+	line, column := tokenStart(after)
+	if endLine != line {
+		return -1
+	}
+	return column - endColumn
+}
+
+// sourceIndex maps line and column positions to byte offsets in the source.
+type sourceIndex struct {
+	src        []byte
+	lineStarts []int
+}
+
+func newSourceIndex(src []byte) sourceIndex {
+	lineStarts := []int{0}
+	if bytes.HasPrefix(src, utf8BOM) {
+		lineStarts[0] = len(utf8BOM)
+	}
+
+	for i, b := range src {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	return sourceIndex{
+		src:        src,
+		lineStarts: lineStarts,
+	}
+}
+
+// offset converts a 1-based line and rune column into a byte offset. Positions
+// past the end of a line are clamped to the end of that line, before its line
+// break.
+func (s sourceIndex) offset(line, column int) int {
+	if line < 1 {
+		return s.lineStarts[0]
+	}
+	if line > len(s.lineStarts) {
+		return len(s.src)
+	}
+
+	offset := s.lineStarts[line-1]
+	for i := 1; i < column && offset < len(s.src) && !s.lineBreakAt(offset); i++ {
+		_, size := utf8.DecodeRune(s.src[offset:])
+		offset += size
+	}
+
+	return offset
+}
+
+func (s sourceIndex) lineBreakAt(offset int) bool {
+	rest := s.src[offset:]
+	return bytes.HasPrefix(rest, []byte("\n")) || bytes.HasPrefix(rest, []byte("\r\n"))
+}
+
+func (s sourceIndex) resolve(p Problem) Problem {
+	p.Offset = s.offset(p.Line, p.Column)
+	p.EndOffset = s.offset(p.EndLine, p.EndColumn)
+
+	for i, edit := range p.Fixes {
+		edit.Offset = s.offset(edit.Line, edit.Column)
+		edit.EndOffset = s.offset(edit.EndLine, edit.EndColumn)
+		p.Fixes[i] = edit
+	}
+
+	return p
+}
+
 type tokenContext struct {
 	lastToken    *token.Token
 	currentToken *token.Token
@@ -49,70 +316,394 @@ type tokenContext struct {
 type lineContext struct {
 	currentLine       string
 	currentLineNumber int
+	// terminator is the line break that ended the line in the source: "\n",
+	// "\r\n" or "" for a final line without one.
+	terminator string
+	// bom is set if the source began with a UTF-8 byte order mark, which is
+	// not part of the first line.
+	bom bool
+	// lines holds every line of the source, so that a linter can look past
+	// the current one.
+	lines []sourceLine
+	// blockScalar holds the lines that are block scalar content.
+	blockScalar blockScalarLines
+}
+
+// totalLines returns the number of lines in the source. A final line break
+// does not start another line.
+func (l lineContext) totalLines() int {
+	return len(l.lines)
+}
+
+// line returns the text of the given 1-based line.
+func (l lineContext) line(n int) string {
+	return l.lines[n-1].text
+}
+
+// inBlockScalar reports whether the given line is block scalar content.
+func (l lineContext) inBlockScalar(n int) bool {
+	return l.blockScalar.contains(n)
+}
+
+var utf8BOM = []byte("\ufeff")
+
+type sourceLine struct {
+	text       string
+	terminator string
+}
+
+// splitLines splits the source into lines without any limit on their length.
+// A leading byte order mark is left out of the first line.
+func splitLines(src []byte) ([]sourceLine, bool) {
+	bom := bytes.HasPrefix(src, utf8BOM)
+	if bom {
+		src = src[len(utf8BOM):]
+	}
+
+	var lines []sourceLine
+
+	for len(src) > 0 {
+		i := bytes.IndexByte(src, '\n')
+		if i < 0 {
+			lines = append(lines, sourceLine{text: string(src)})
+			break
+		}
+
+		line := sourceLine{text: string(src[:i]), terminator: "\n"}
+		if strings.HasSuffix(line.text, "\r") {
+			line.text = line.text[:len(line.text)-1]
+			line.terminator = "\r\n"
+		}
+
+		lines = append(lines, line)
+		src = src[i+1:]
+	}
+
+	return lines, bom
 }
 
 type Linter interface {
+	// Name returns the rule id reported with every problem, as used in the
+	// rules section of the config.
+	Name() string
+	// CheckToken checks a token. LintAll yields problems in order while it
+	// walks the source, so a problem must not start before the line of the
+	// previous token, or of the current token if it is the first one.
 	CheckToken(tokenContext) iter.Seq[Problem]
+	// CheckLine checks a line. A problem must not start before the line
+	// being checked.
 	CheckLine(lineContext) iter.Seq[Problem]
 }
 
+// scopedLinter is implemented by linters that keep state while walking a
+// source. LintAll asks for a fresh instance on every run so that the state
+// never leaks from one source into the next.
+type scopedLinter interface {
+	Linter
+	scope() Linter
+}
+
+// fileLinter is implemented by linters that check the parsed structure of the
+// source, for problems that cannot be told apart on the tokens alone. It is
+// only called for source that parses.
+type fileLinter interface {
+	Linter
+	checkFile(*ast.File) iter.Seq[Problem]
+}
+
+func scoped(linters []Linter) []Linter {
+	scoped := make([]Linter, len(linters))
+
+	for i, lint := range linters {
+		if s, ok := lint.(scopedLinter); ok {
+			lint = s.scope()
+		}
+		scoped[i] = lint
+	}
+
+	return scoped
+}
+
+// Chain is a list of linters to run together. The built-in linters hold no
+// state of their own between runs, so a Chain may be shared by concurrent
+// LintAll calls.
 type Chain []Linter
 
+// Problem is a single finding reported by a linter. Lines and columns are
+// 1-based and columns count runes. The end position is exclusive. Offset and
+// EndOffset are the byte offsets of the same range in the source.
 type Problem struct {
-	Line   int
-	Column int
-	Error  error
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Offset    int
+	EndOffset int
+	// Rule is the name of the linter that reported the problem.
+	Rule  string
+	Error error
+	// Fixes holds the edits that resolve the problem, if it can be fixed
+	// automatically. The edits are applied together or not at all.
+	Fixes []Edit
+}
+
+// Edit replaces the text in a range of the source with NewText. Positions
+// follow the same conventions as Problem.
+type Edit struct {
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Offset    int
+	EndOffset int
+	NewText   string
+}
+
+// Message describes the problem without the generic "lint error" prefix of
+// its error, for output that already says what kind of finding it is.
+func (p Problem) Message() string {
+	if wrapped, ok := p.Error.(interface{ Unwrap() []error }); ok {
+		if errs := wrapped.Unwrap(); len(errs) == 2 && errs[0] == lintError {
+			return errs[1].Error()
+		}
+	}
+	return p.Error.Error()
+}
+
+func (p Problem) withFix(edits ...Edit) Problem {
+	p.Fixes = edits
+	return p
 }
 
 // LintAll performs linting on the entire source code and returns an iterator of all errors found.
+// The source is walked once, line by line, with every linter checking each line and then each token
+// that starts on it. Problems are yielded ordered by line, column and rule, without duplicates.
+// If the source is not valid YAML, the syntax error is reported and only the line based checks run,
+// as the token stream cannot be trusted.
 func LintAll(src []byte, linters ...Linter) iter.Seq[Problem] {
-	tokens := lexer.Tokenize(string(src))
+	return func(yield func(Problem) bool) {
+		_ = walk(context.Background(), src, linters, yield)
+	}
+}
+
+// LintContext performs linting like LintAll, but stops as soon as ctx is done. It returns the
+// problems found up to that point along with ctx.Err().
+func LintContext(ctx context.Context, src []byte, linters ...Linter) ([]Problem, error) {
+	var problems []Problem
+
+	err := walk(ctx, src, linters, func(problem Problem) bool {
+		problems = append(problems, problem)
+		return true
+	})
+
+	return problems, err
+}
+
+// walk runs the linters over the source, checking for cancellation between lines, tokens and
+// linters. It returns ctx.Err() if it was cancelled.
+func walk(ctx context.Context, src []byte, linters []Linter, yield func(Problem) bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lines, bom := splitLines(src)
+	index := newSourceIndex(src)
+
+	// The byte order mark is not part of the YAML content, and positions on
+	// the first line are counted from after it.
+	content := src
+	if bom {
+		content = src[len(utf8BOM):]
+	}
+
+	var timings *fileTimings
+	if shared := timingsFrom(ctx); shared != nil {
+		timings = &fileTimings{linters: make(map[string]LinterTimings)}
+		defer shared.merge(timings)
+	}
+
+	var begin time.Time
+	if timings != nil {
+		begin = time.Now()
+	}
+
+	tokens := lexer.Tokenize(string(content))
 	// tokens.Dump()
 
-	var lines []string
-	lineScanner := bufio.NewScanner(bytes.NewReader(src))
-	for lineScanner.Scan() {
-		lines = append(lines, lineScanner.Text())
+	if timings != nil {
+		timings.lex += time.Since(begin)
 	}
 
-	seqFunc := func(yield func(Problem) bool) {
-		for _, lint := range linters {
-			for i := 0; i < len(lines); i++ {
-				lineContext := lineContext{
-					currentLine:       lines[i],
-					currentLineNumber: i + 1,
-				}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-				for problem := range lint.CheckLine(lineContext) {
-					if !yield(problem) {
-						return
-					}
-				}
-			}
+	linters = scoped(linters)
+
+	var pending problemQueue
+	next := 0
+
+	file, syntax, broken := parse(content)
+	if broken {
+		pending.push(index.resolve(syntax))
+		tokens = nil
+	}
 
-			for i := 0; i < len(tokens); i++ {
-				srcContext := tokenContext{
-					currentToken: tokens[i],
+	disabled := findDisabledLines(tokens)
+	blockScalar := findBlockScalarLines(tokens)
+
+	report := func(lint Linter, problem Problem) {
+		problem.Rule = lint.Name()
+		if !disabled.disables(problem) {
+			pending.push(index.resolve(problem))
+		}
+	}
+
+	checkTokens := func(line int) error {
+		for ; next < len(tokens) && tokens[next].Position.Line <= line; next++ {
+			tokenCtx := newTokenContext(tokens, next)
+
+			for _, lint := range linters {
+				if err := ctx.Err(); err != nil {
+					return err
 				}
 
-				if i >= 1 {
-					srcContext.lastToken = tokens[i-1]
+				if timings != nil {
+					begin = time.Now()
 				}
 
-				if i < len(tokens)-1 {
-					srcContext.nextToken = tokens[i+1]
+				for problem := range lint.CheckToken(tokenCtx) {
+					report(lint, problem)
 				}
 
-				for problem := range lint.CheckToken(srcContext) {
-					if !yield(problem) {
-						return
-					}
+				if timings != nil {
+					timings.addToken(lint.Name(), time.Since(begin))
 				}
 			}
 		}
+
+		return nil
+	}
+
+	if !broken {
+		for _, lint := range linters {
+			fileLint, ok := lint.(fileLinter)
+			if !ok {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if timings != nil {
+				begin = time.Now()
+			}
+
+			for problem := range fileLint.checkFile(file) {
+				report(lint, problem)
+			}
+
+			if timings != nil {
+				timings.addFile(lint.Name(), time.Since(begin))
+			}
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		lineCtx := lineContext{
+			currentLine:       lines[i].text,
+			currentLineNumber: i + 1,
+			terminator:        lines[i].terminator,
+			bom:               bom,
+			lines:             lines,
+			blockScalar:       blockScalar,
+		}
+
+		for _, lint := range linters {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if timings != nil {
+				begin = time.Now()
+			}
+
+			for problem := range lint.CheckLine(lineCtx) {
+				report(lint, problem)
+			}
+
+			if timings != nil {
+				timings.addLine(lint.Name(), time.Since(begin))
+			}
+		}
+
+		if err := checkTokens(lineCtx.currentLineNumber); err != nil {
+			return err
+		}
+
+		// Later lines and tokens can still report problems on the last
+		// token seen, as the token before the next one. Linters must not
+		// report anything earlier, see Linter.
+		watermark := lineCtx.currentLineNumber + 1
+		if next > 0 {
+			watermark = min(watermark, tokens[next-1].Position.Line)
+		}
+
+		if !pending.flush(watermark, yield) {
+			return nil
+		}
+	}
+
+	if err := checkTokens(math.MaxInt); err != nil {
+		return err
+	}
+
+	pending.flush(math.MaxInt, yield)
+	return nil
+}
+
+// parse parses the source, and returns a problem for the first syntax error
+// in it if there is one.
+func parse(src []byte) (*ast.File, Problem, bool) {
+	file, err := parser.ParseBytes(src, 0, parser.AllowDuplicateMapKey())
+	if err == nil {
+		return file, Problem{}, false
+	}
+
+	var syntaxErr *yaml.SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Token == nil || syntaxErr.Token.Position == nil {
+		problem := problem(1, 1, fmt.Errorf("%w: %w", ErrSyntax, err))
+		problem.Rule = "syntax"
+		return nil, problem, true
+	}
+
+	err = fmt.Errorf("%w: %s", ErrSyntax, syntaxErr.Message)
+	tk := syntaxErr.Token
+
+	syntax := tokenProblem(tk, err)
+	if syntax.EndLine == syntax.Line && syntax.EndColumn == syntax.Column {
+		syntax = problem(syntax.Line, syntax.Column, err)
+	}
+	syntax.Rule = "syntax"
+
+	return nil, syntax, true
+}
+
+func newTokenContext(tokens token.Tokens, i int) tokenContext {
+	ctx := tokenContext{
+		currentToken: tokens[i],
+	}
+
+	if i >= 1 {
+		ctx.lastToken = tokens[i-1]
+	}
+
+	if i < len(tokens)-1 {
+		ctx.nextToken = tokens[i+1]
 	}
 
-	return seqFunc
+	return ctx
 }
 
 // Lint performs linting on the entire source code and returns the first error found.
@@ -123,3 +714,134 @@ func Lint(src []byte, linters ...Linter) *Problem {
 
 	return nil
 }
+
+// maxFixIterations bounds the number of lint passes made by Fix, in case fixes
+// from different linters keep undoing each other.
+const maxFixIterations = 10
+
+// Fix applies the fixes of all problems found in the source, linting again
+// after every pass until no more fixes apply. It returns the fixed source and
+// the problems that remain in it.
+func Fix(src []byte, linters ...Linter) ([]byte, []Problem) {
+	fixed, problems, _ := FixContext(context.Background(), src, linters...)
+	return fixed, problems
+}
+
+// FixContext fixes the source like Fix, but stops as soon as ctx is done. It
+// then returns the source as fixed by the passes that completed, along with
+// ctx.Err().
+func FixContext(ctx context.Context, src []byte, linters ...Linter) ([]byte, []Problem, error) {
+	var problems []Problem
+
+	for range maxFixIterations {
+		var err error
+
+		problems, err = LintContext(ctx, src, linters...)
+		if err != nil {
+			return src, problems, err
+		}
+
+		fixed, ok := applyFixes(src, problems)
+		if !ok || !sameValues(src, fixed) {
+			return src, problems, nil
+		}
+
+		src = fixed
+	}
+
+	problems, err := LintContext(ctx, src, linters...)
+	return src, problems, err
+}
+
+// applyFixes applies every fix that does not overlap a fix applied before it
+// and reports whether anything was applied. Fixes are taken in source order.
+func applyFixes(src []byte, problems []Problem) ([]byte, bool) {
+	var fixes [][]Edit
+
+	for _, problem := range problems {
+		if len(problem.Fixes) == 0 {
+			continue
+		}
+
+		edits := slices.Clone(problem.Fixes)
+		slices.SortFunc(edits, func(a, b Edit) int {
+			return cmp.Compare(a.Offset, b.Offset)
+		})
+		fixes = append(fixes, edits)
+	}
+
+	slices.SortStableFunc(fixes, func(a, b []Edit) int {
+		return cmp.Compare(a[0].Offset, b[0].Offset)
+	})
+
+	var (
+		out       bytes.Buffer
+		applied   bool
+		lastStart = -1
+		lastEnd   = 0
+	)
+
+	for _, edits := range fixes {
+		if !canApply(edits, lastStart, lastEnd) {
+			continue
+		}
+
+		for _, edit := range edits {
+			out.Write(src[lastEnd:edit.Offset])
+			out.WriteString(edit.NewText)
+			lastStart, lastEnd = edit.Offset, edit.EndOffset
+		}
+		applied = true
+	}
+
+	out.Write(src[lastEnd:])
+	return out.Bytes(), applied
+}
+
+// sameValues reports whether two sources decode to the same documents, so
+// that a fix which would change the data is never applied. Source that does
+// not decode in the first place has nothing to protect.
+func sameValues(src, fixed []byte) bool {
+	before, err := decodeAll(src)
+	if err != nil {
+		return true
+	}
+
+	after, err := decodeAll(fixed)
+	if err != nil {
+		return false
+	}
+
+	// Formatted rather than compared with reflect.DeepEqual, which never
+	// considers NaN equal to itself.
+	return fmt.Sprintf("%#v", before) == fmt.Sprintf("%#v", after)
+}
+
+func decodeAll(src []byte) ([]any, error) {
+	var docs []any
+
+	dec := yaml.NewDecoder(bytes.NewReader(src), yaml.AllowDuplicateMapKey())
+	for {
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
+func canApply(edits []Edit, lastStart, lastEnd int) bool {
+	for _, edit := range edits {
+		// Two insertions at the same offset conflict as well, as their
+		// order would be ambiguous.
+		if edit.Offset < lastEnd || edit.Offset == lastStart || edit.EndOffset < edit.Offset {
+			return false
+		}
+		lastStart, lastEnd = edit.Offset, edit.EndOffset
+	}
+
+	return true
+}
@@ -1,22 +1,24 @@
 package lint
 
 import (
-	"errors"
 	"iter"
 
 	"github.com/goccy/go-yaml/token"
 )
 
 var (
-	ErrAnchorDuplicated = errors.New("anchor is duplicated")
-	ErrAnchorUndeclared = errors.New("alias references an undeclared anchor")
-	ErrAnchorNotUsed    = errors.New("anchor is declared but not used")
+	ErrAnchorDuplicated = newError("anchor is duplicated")
+	ErrAnchorUndeclared = newError("alias references an undeclared anchor")
+	ErrAnchorNotUsed    = newError("anchor is declared but not used")
 )
 
 type anchors struct {
 	AnchorOpts
-	declaredAnchors map[string]token.Token
-	usedAnchors     map[string]struct{}
+	declaredAnchors map[string]struct{}
+	// unusedAnchors holds the last declaration of every anchor that no alias
+	// refers to. It is filled from the whole token stream on the first token,
+	// so that unused anchors can be reported where they are declared.
+	unusedAnchors map[*token.Token]struct{}
 }
 
 type AnchorOpts struct {
@@ -26,67 +28,102 @@ type AnchorOpts struct {
 }
 
 func Anchors(opts AnchorOpts) Linter {
-	return anchors{
+	return &anchors{
 		AnchorOpts:      opts,
-		declaredAnchors: make(map[string]token.Token),
-		usedAnchors:     make(map[string]struct{}),
+		declaredAnchors: make(map[string]struct{}),
 	}
 }
 
-func (a anchors) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+func (a *anchors) Name() string {
+	return "anchors"
+}
+
+func (a *anchors) scope() Linter {
+	return Anchors(a.AnchorOpts)
+}
+
+func (a *anchors) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {
+		if a.ForbidUnusedAnchors && a.unusedAnchors == nil {
+			a.unusedAnchors = findUnusedAnchors(ctx.currentToken)
+		}
+
 		if ctx.currentToken.Type == token.AnchorType && ctx.nextToken != nil {
 			anchorName := ctx.nextToken.Value
 
 			if _, ok := a.declaredAnchors[anchorName]; ok && a.ForbidDuplicatedAnchors {
-				problem := problem(
-					ctx.currentToken.Position.Line,
-					ctx.currentToken.Position.Column,
-					ErrAnchorDuplicated,
-				)
+				problem := nameProblem(ctx, ErrAnchorDuplicated)
 				if !yield(problem) {
 					return
 				}
 			}
 
-			a.declaredAnchors[anchorName] = *ctx.currentToken
+			if _, ok := a.unusedAnchors[ctx.currentToken]; ok {
+				problem := nameProblem(ctx, ErrAnchorNotUsed)
+				if !yield(problem) {
+					return
+				}
+			}
+
+			a.declaredAnchors[anchorName] = struct{}{}
 		}
 
 		if ctx.currentToken.Type == token.AliasType && ctx.nextToken != nil {
 			anchorName := ctx.nextToken.Value
 
 			if _, ok := a.declaredAnchors[anchorName]; !ok && a.ForbidUndeclaredAliases {
-				problem := problem(
-					ctx.currentToken.Position.Line,
-					ctx.currentToken.Position.Column,
-					ErrAnchorUndeclared,
-				)
+				problem := nameProblem(ctx, ErrAnchorUndeclared)
 				if !yield(problem) {
 					return
 				}
 			}
+		}
+	}
+}
+
+// findUnusedAnchors walks the whole token stream that tk belongs to and
+// returns the last declaration of every anchor name that no alias uses.
+func findUnusedAnchors(tk *token.Token) map[*token.Token]struct{} {
+	for tk.Prev != nil {
+		tk = tk.Prev
+	}
+
+	lastDeclarations := make(map[string]*token.Token)
+	usedAnchors := make(map[string]struct{})
 
-			a.usedAnchors[anchorName] = struct{}{}
+	for ; tk != nil && tk.Next != nil; tk = tk.Next {
+		switch tk.Type {
+		case token.AnchorType:
+			lastDeclarations[tk.Next.Value] = tk
+		case token.AliasType:
+			usedAnchors[tk.Next.Value] = struct{}{}
 		}
+	}
 
-		// Final token
-		if a.ForbidUnusedAnchors && ctx.nextToken == nil {
-			for anchorName, anchor := range a.declaredAnchors {
-				if _, ok := a.usedAnchors[anchorName]; !ok {
-					problem := problem(
-						anchor.Position.Line,
-						anchor.Position.Column,
-						ErrAnchorNotUsed,
-					)
-					if !yield(problem) {
-						return
-					}
-				}
-			}
+	unusedAnchors := make(map[*token.Token]struct{})
+	for anchorName, declaration := range lastDeclarations {
+		if _, ok := usedAnchors[anchorName]; !ok {
+			unusedAnchors[declaration] = struct{}{}
 		}
 	}
+
+	return unusedAnchors
+}
+
+// nameProblem covers an anchor or alias indicator together with the name that
+// follows it.
+func nameProblem(ctx tokenContext, err error) Problem {
+	endLine, endColumn := tokenEnd(ctx.nextToken)
+
+	return problemRange(
+		ctx.currentToken.Position.Line,
+		ctx.currentToken.Position.Column,
+		endLine,
+		endColumn,
+		err,
+	)
 }
 
-func (a anchors) CheckLine(ctx lineContext) iter.Seq[Problem] {
+func (a *anchors) CheckLine(ctx lineContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {}
 }
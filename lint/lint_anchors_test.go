@@ -16,15 +16,15 @@ func TestAnchorForbidUndeclaredAliases(t *testing.T) {
 	const fail1 = `
 ---
 - &anchor
-	foo: bar
+  foo: bar
 - *unknown`
 
 	const fail2 = `
 ---
 - &anchor
-	foo: bar
+  foo: bar
 - <<: *unknown
-	extra: value`
+  extra: value`
 
 	t.Run("Pass", func(t *testing.T) {
 		for _, src := range []string{pass1} {
@@ -1,20 +1,18 @@
 package lint
 
 import (
-	"errors"
 	"iter"
-	"strings"
 
 	"github.com/goccy/go-yaml/token"
 )
 
 var (
-	ErrBracesForbidden          = errors.New("braces are forbidden")
-	ErrBracesNonEmptyForbidden  = errors.New("non empty braces are forbidden")
-	ErrBracesTooFewSpaces       = errors.New("too few spaces inside braces")
-	ErrBracesTooManySpaces      = errors.New("too many spaces inside braces")
-	ErrBracesTooFewSpacesEmpty  = errors.New("too few spaces inside empty braces")
-	ErrBracesTooManySpacesEmpty = errors.New("too many spaces inside empty braces")
+	ErrBracesForbidden          = newError("braces are forbidden")
+	ErrBracesNonEmptyForbidden  = newError("non empty braces are forbidden")
+	ErrBracesTooFewSpaces       = newError("too few spaces inside braces")
+	ErrBracesTooManySpaces      = newError("too many spaces inside braces")
+	ErrBracesTooFewSpacesEmpty  = newError("too few spaces inside empty braces")
+	ErrBracesTooManySpacesEmpty = newError("too many spaces inside empty braces")
 )
 
 type ForbidBraces int
@@ -33,6 +31,10 @@ type Braces struct {
 	MaxSpacesInsideEmpty int
 }
 
+func (b Braces) Name() string {
+	return "braces"
+}
+
 func (b Braces) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {
 		if b.Forbid == ForbidBracesAll || b.Forbid == ForbidBracesNonEmpty {
@@ -51,123 +53,82 @@ func (b Braces) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 }
 
 func (b Braces) checkSpacesStart(ctx tokenContext, yield func(Problem) bool) bool {
-	if ctx.nextToken == nil {
+	if ctx.nextToken == nil || ctx.currentToken.Type != token.MappingStartType {
 		return true
 	}
 
-	if ctx.currentToken.Type == token.MappingStartType {
-		if ctx.nextToken.Type == token.MappingEndType {
-			spaces := strings.Count(ctx.nextToken.Origin, " ")
-
-			if spaces < b.MinSpacesInsideEmpty {
-				problem := problem(
-					ctx.nextToken.Position.Line,
-					ctx.nextToken.Position.Column,
-					ErrBracesTooFewSpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			if spaces > b.MaxSpacesInsideEmpty {
-				problem := problem(
-					ctx.nextToken.Position.Line,
-					ctx.nextToken.Position.Column,
-					ErrBracesTooManySpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			return true
-		}
-
-		leadingSpaces := leadingSpaces(ctx.nextToken.Origin)
+	spaces := spacesBetween(ctx.currentToken, ctx.nextToken)
+	if spaces < 0 {
+		return true
+	}
 
-		if leadingSpaces < b.MinSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracesTooFewSpaces,
-			)
+	if ctx.nextToken.Type == token.MappingEndType {
+		if spaces < b.MinSpacesInsideEmpty {
+			problem := spanProblem(ctx.currentToken, ctx.nextToken, ErrBracesTooFewSpacesEmpty).
+				withFix(respace(ctx.currentToken, spaces, b.MinSpacesInsideEmpty))
 			if !yield(problem) {
 				return false
 			}
 		}
 
-		if leadingSpaces > b.MaxSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracesTooManySpaces,
-			)
+		if spaces > b.MaxSpacesInsideEmpty {
+			problem := spacesProblem(ctx.currentToken, spaces, ErrBracesTooManySpacesEmpty).
+				withFix(respace(ctx.currentToken, spaces, b.MaxSpacesInsideEmpty))
 			if !yield(problem) {
 				return false
 			}
 		}
+
+		return true
+	}
+
+	if spaces < b.MinSpacesInside {
+		problem := tokenProblem(ctx.currentToken, ErrBracesTooFewSpaces).
+			withFix(respace(ctx.currentToken, spaces, b.MinSpacesInside))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	if spaces > b.MaxSpacesInside {
+		problem := spacesProblem(ctx.currentToken, spaces, ErrBracesTooManySpaces).
+			withFix(respace(ctx.currentToken, spaces, b.MaxSpacesInside))
+		if !yield(problem) {
+			return false
+		}
 	}
 
-	return false
+	return true
 }
 
 func (b Braces) checkSpacesEnd(ctx tokenContext, yield func(Problem) bool) bool {
-	if ctx.lastToken == nil {
+	if ctx.lastToken == nil || ctx.currentToken.Type != token.MappingEndType {
 		return true
 	}
 
-	if ctx.currentToken.Type == token.MappingEndType {
-		if ctx.lastToken.Type == token.MappingStartType {
-			leadingSpaces := strings.Count(ctx.currentToken.Origin, " ")
-
-			if leadingSpaces < b.MinSpacesInsideEmpty {
-				problem := problem(
-					ctx.lastToken.Position.Line,
-					ctx.lastToken.Position.Column,
-					ErrBracesTooFewSpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			if leadingSpaces > b.MaxSpacesInsideEmpty {
-				problem := problem(
-					ctx.lastToken.Position.Line,
-					ctx.lastToken.Position.Column,
-					ErrBracesTooManySpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			return true
-		}
+	// Empty braces are checked from the opening brace.
+	if ctx.lastToken.Type == token.MappingStartType {
+		return true
+	}
 
-		trailingSpaces := trailingSpaces(ctx.lastToken.Origin)
+	spaces := spacesBetween(ctx.lastToken, ctx.currentToken)
+	if spaces < 0 {
+		return true
+	}
 
-		if trailingSpaces < b.MinSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracesTooFewSpaces,
-			)
-			if !yield(problem) {
-				return false
-			}
+	if spaces < b.MinSpacesInside {
+		problem := tokenProblem(ctx.currentToken, ErrBracesTooFewSpaces).
+			withFix(respace(ctx.lastToken, spaces, b.MinSpacesInside))
+		if !yield(problem) {
+			return false
 		}
+	}
 
-		if trailingSpaces > b.MaxSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracesTooManySpaces,
-			)
-			if !yield(problem) {
-				return false
-			}
+	if spaces > b.MaxSpacesInside {
+		problem := spacesProblem(ctx.lastToken, spaces, ErrBracesTooManySpaces).
+			withFix(respace(ctx.lastToken, spaces, b.MaxSpacesInside))
+		if !yield(problem) {
+			return false
 		}
 	}
 
@@ -184,16 +145,21 @@ func (b Braces) checkBraces(ctx tokenContext, yield func(Problem) bool) bool {
 		if ctx.currentToken.Value != "}" {
 			return true
 		}
+
+		// Forbidden braces are reported once from the opening brace, so
+		// there is nothing left to check on the closing one.
+		if b.Forbid == ForbidBracesAll {
+			return false
+		}
+		return ctx.lastToken == nil || ctx.lastToken.Type == token.MappingStartType
 	default:
 		return true
 	}
 
+	end := flowEnd(ctx.currentToken, token.MappingEndType)
+
 	if b.Forbid == ForbidBracesAll {
-		problem := problem(
-			ctx.currentToken.Position.Line,
-			ctx.currentToken.Position.Column,
-			ErrBracesForbidden,
-		)
+		problem := spanProblem(ctx.currentToken, end, ErrBracesForbidden)
 		if !yield(problem) {
 			return false
 		}
@@ -204,50 +170,16 @@ func (b Braces) checkBraces(ctx tokenContext, yield func(Problem) bool) bool {
 	if b.Forbid == ForbidBracesNonEmpty {
 		if ctx.nextToken != nil &&
 			ctx.nextToken.Type == token.MappingEndType &&
-			ctx.currentToken.Type == token.MappingStartType {
-			spaces := strings.Count(ctx.nextToken.Origin, " ")
-
-			if spaces > 0 {
-				problem := problem(
-					ctx.nextToken.Position.Line,
-					ctx.nextToken.Position.Column,
-					ErrBracesNonEmptyForbidden,
-				)
-				if !yield(problem) {
-					return false
-				}
-
-				return false
-			}
-		} else if ctx.lastToken != nil &&
-			ctx.lastToken.Type == token.MappingStartType &&
-			ctx.currentToken.Type == token.MappingEndType {
-			spaces := strings.Count(ctx.currentToken.Origin, " ")
-
-			if spaces > 0 {
-				problem := problem(
-					ctx.currentToken.Position.Line,
-					ctx.currentToken.Position.Column,
-					ErrBracesNonEmptyForbidden,
-				)
-				if !yield(problem) {
-					return false
-				}
-
-				return false
-			}
-		} else {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracesNonEmptyForbidden,
-			)
-			if !yield(problem) {
-				return false
-			}
+			spacesBetween(ctx.currentToken, ctx.nextToken) == 0 {
+			return true
+		}
 
+		problem := spanProblem(ctx.currentToken, end, ErrBracesNonEmptyForbidden)
+		if !yield(problem) {
 			return false
 		}
+
+		return false
 	}
 
 	return true
@@ -171,3 +171,44 @@ object: {   }
 		})
 	}
 }
+
+func TestBracesFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name: "MinSpacesInside",
+			lint: Braces{
+				MinSpacesInside: 1,
+				MaxSpacesInside: 1,
+			},
+			input:    "object: {key1: 4, key2: 8}\n",
+			expected: "object: { key1: 4, key2: 8 }\n",
+		},
+		{
+			name: "MaxSpacesInside",
+			lint: Braces{
+				MinSpacesInside: 0,
+				MaxSpacesInside: 0,
+			},
+			input:    "object: {   key1: 4, key2: { key3: 8 }  }\n",
+			expected: "object: {key1: 4, key2: {key3: 8}}\n",
+		},
+		{
+			name: "MinSpacesInsideEmpty",
+			lint: Braces{
+				MinSpacesInsideEmpty: 1,
+				MaxSpacesInsideEmpty: 1,
+			},
+			input:    "object: {}\n",
+			expected: "object: { }\n",
+		},
+		{
+			name: "MaxSpacesInsideEmpty",
+			lint: Braces{
+				MinSpacesInsideEmpty: 0,
+				MaxSpacesInsideEmpty: 0,
+			},
+			input:    "object: {    }\n",
+			expected: "object: {}\n",
+		},
+	})
+}
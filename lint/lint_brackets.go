@@ -1,20 +1,18 @@
 package lint
 
 import (
-	"errors"
 	"iter"
-	"strings"
 
 	"github.com/goccy/go-yaml/token"
 )
 
 var (
-	ErrBracketsForbidden          = errors.New("brackets are forbidden")
-	ErrBracketsNonEmptyForbidden  = errors.New("non empty brackets are forbidden")
-	ErrBracketsTooFewSpaces       = errors.New("too few spaces inside brackets")
-	ErrBracketsTooManySpaces      = errors.New("too many spaces inside brackets")
-	ErrBracketsTooFewSpacesEmpty  = errors.New("too few spaces inside empty brackets")
-	ErrBracketsTooManySpacesEmpty = errors.New("too many spaces inside empty brackets")
+	ErrBracketsForbidden          = newError("brackets are forbidden")
+	ErrBracketsNonEmptyForbidden  = newError("non empty brackets are forbidden")
+	ErrBracketsTooFewSpaces       = newError("too few spaces inside brackets")
+	ErrBracketsTooManySpaces      = newError("too many spaces inside brackets")
+	ErrBracketsTooFewSpacesEmpty  = newError("too few spaces inside empty brackets")
+	ErrBracketsTooManySpacesEmpty = newError("too many spaces inside empty brackets")
 )
 
 type ForbidBrackets int
@@ -33,6 +31,10 @@ type Brackets struct {
 	MaxSpacesInsideEmpty int
 }
 
+func (b Brackets) Name() string {
+	return "brackets"
+}
+
 func (b Brackets) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {
 		if b.Forbid == ForbidBracketsAll || b.Forbid == ForbidBracketsNonEmpty {
@@ -51,123 +53,82 @@ func (b Brackets) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 }
 
 func (b Brackets) checkSpacesStart(ctx tokenContext, yield func(Problem) bool) bool {
-	if ctx.nextToken == nil {
+	if ctx.nextToken == nil || ctx.currentToken.Type != token.SequenceStartType {
 		return true
 	}
 
-	if ctx.currentToken.Type == token.SequenceStartType {
-		if ctx.nextToken.Type == token.SequenceEndType {
-			spaces := strings.Count(ctx.nextToken.Origin, " ")
-
-			if spaces < b.MinSpacesInsideEmpty {
-				problem := problem(
-					ctx.nextToken.Position.Line,
-					ctx.nextToken.Position.Column,
-					ErrBracketsTooFewSpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			if spaces > b.MaxSpacesInsideEmpty {
-				problem := problem(
-					ctx.nextToken.Position.Line,
-					ctx.nextToken.Position.Column,
-					ErrBracketsTooManySpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			return true
-		}
-
-		leadingSpaces := leadingSpaces(ctx.nextToken.Origin)
+	spaces := spacesBetween(ctx.currentToken, ctx.nextToken)
+	if spaces < 0 {
+		return true
+	}
 
-		if leadingSpaces < b.MinSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracketsTooFewSpaces,
-			)
+	if ctx.nextToken.Type == token.SequenceEndType {
+		if spaces < b.MinSpacesInsideEmpty {
+			problem := spanProblem(ctx.currentToken, ctx.nextToken, ErrBracketsTooFewSpacesEmpty).
+				withFix(respace(ctx.currentToken, spaces, b.MinSpacesInsideEmpty))
 			if !yield(problem) {
 				return false
 			}
 		}
 
-		if leadingSpaces > b.MaxSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracketsTooManySpaces,
-			)
+		if spaces > b.MaxSpacesInsideEmpty {
+			problem := spacesProblem(ctx.currentToken, spaces, ErrBracketsTooManySpacesEmpty).
+				withFix(respace(ctx.currentToken, spaces, b.MaxSpacesInsideEmpty))
 			if !yield(problem) {
 				return false
 			}
 		}
+
+		return true
+	}
+
+	if spaces < b.MinSpacesInside {
+		problem := tokenProblem(ctx.currentToken, ErrBracketsTooFewSpaces).
+			withFix(respace(ctx.currentToken, spaces, b.MinSpacesInside))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	if spaces > b.MaxSpacesInside {
+		problem := spacesProblem(ctx.currentToken, spaces, ErrBracketsTooManySpaces).
+			withFix(respace(ctx.currentToken, spaces, b.MaxSpacesInside))
+		if !yield(problem) {
+			return false
+		}
 	}
 
-	return false
+	return true
 }
 
 func (b Brackets) checkSpacesEnd(ctx tokenContext, yield func(Problem) bool) bool {
-	if ctx.lastToken == nil {
+	if ctx.lastToken == nil || ctx.currentToken.Type != token.SequenceEndType {
 		return true
 	}
 
-	if ctx.currentToken.Type == token.SequenceEndType {
-		if ctx.lastToken.Type == token.SequenceStartType {
-			leadingSpaces := strings.Count(ctx.currentToken.Origin, " ")
-
-			if leadingSpaces < b.MinSpacesInsideEmpty {
-				problem := problem(
-					ctx.lastToken.Position.Line,
-					ctx.lastToken.Position.Column,
-					ErrBracketsTooFewSpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			if leadingSpaces > b.MaxSpacesInsideEmpty {
-				problem := problem(
-					ctx.lastToken.Position.Line,
-					ctx.lastToken.Position.Column,
-					ErrBracketsTooManySpacesEmpty,
-				)
-				if !yield(problem) {
-					return false
-				}
-			}
-
-			return true
-		}
+	// Empty brackets are checked from the opening bracket.
+	if ctx.lastToken.Type == token.SequenceStartType {
+		return true
+	}
 
-		trailingSpaces := trailingSpaces(ctx.lastToken.Origin)
+	spaces := spacesBetween(ctx.lastToken, ctx.currentToken)
+	if spaces < 0 {
+		return true
+	}
 
-		if trailingSpaces < b.MinSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracketsTooFewSpaces,
-			)
-			if !yield(problem) {
-				return false
-			}
+	if spaces < b.MinSpacesInside {
+		problem := tokenProblem(ctx.currentToken, ErrBracketsTooFewSpaces).
+			withFix(respace(ctx.lastToken, spaces, b.MinSpacesInside))
+		if !yield(problem) {
+			return false
 		}
+	}
 
-		if trailingSpaces > b.MaxSpacesInside {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracketsTooManySpaces,
-			)
-			if !yield(problem) {
-				return false
-			}
+	if spaces > b.MaxSpacesInside {
+		problem := spacesProblem(ctx.lastToken, spaces, ErrBracketsTooManySpaces).
+			withFix(respace(ctx.lastToken, spaces, b.MaxSpacesInside))
+		if !yield(problem) {
+			return false
 		}
 	}
 
@@ -184,16 +145,21 @@ func (b Brackets) checkBrackets(ctx tokenContext, yield func(Problem) bool) bool
 		if ctx.currentToken.Value != "]" {
 			return true
 		}
+
+		// Forbidden brackets are reported once from the opening bracket, so
+		// there is nothing left to check on the closing one.
+		if b.Forbid == ForbidBracketsAll {
+			return false
+		}
+		return ctx.lastToken == nil || ctx.lastToken.Type == token.SequenceStartType
 	default:
 		return true
 	}
 
+	end := flowEnd(ctx.currentToken, token.SequenceEndType)
+
 	if b.Forbid == ForbidBracketsAll {
-		problem := problem(
-			ctx.currentToken.Position.Line,
-			ctx.currentToken.Position.Column,
-			ErrBracketsForbidden,
-		)
+		problem := spanProblem(ctx.currentToken, end, ErrBracketsForbidden)
 		if !yield(problem) {
 			return false
 		}
@@ -204,50 +170,16 @@ func (b Brackets) checkBrackets(ctx tokenContext, yield func(Problem) bool) bool
 	if b.Forbid == ForbidBracketsNonEmpty {
 		if ctx.nextToken != nil &&
 			ctx.nextToken.Type == token.SequenceEndType &&
-			ctx.currentToken.Type == token.SequenceStartType {
-			spaces := strings.Count(ctx.nextToken.Origin, " ")
-
-			if spaces > 0 {
-				problem := problem(
-					ctx.nextToken.Position.Line,
-					ctx.nextToken.Position.Column,
-					ErrBracketsNonEmptyForbidden,
-				)
-				if !yield(problem) {
-					return false
-				}
-
-				return false
-			}
-		} else if ctx.lastToken != nil &&
-			ctx.lastToken.Type == token.SequenceStartType &&
-			ctx.currentToken.Type == token.SequenceEndType {
-			spaces := strings.Count(ctx.currentToken.Origin, " ")
-
-			if spaces > 0 {
-				problem := problem(
-					ctx.currentToken.Position.Line,
-					ctx.currentToken.Position.Column,
-					ErrBracketsNonEmptyForbidden,
-				)
-				if !yield(problem) {
-					return false
-				}
-
-				return false
-			}
-		} else {
-			problem := problem(
-				ctx.currentToken.Position.Line,
-				ctx.currentToken.Position.Column,
-				ErrBracketsNonEmptyForbidden,
-			)
-			if !yield(problem) {
-				return false
-			}
+			spacesBetween(ctx.currentToken, ctx.nextToken) == 0 {
+			return true
+		}
 
+		problem := spanProblem(ctx.currentToken, end, ErrBracketsNonEmptyForbidden)
+		if !yield(problem) {
 			return false
 		}
+
+		return false
 	}
 
 	return true
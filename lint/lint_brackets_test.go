@@ -171,3 +171,44 @@ object: [   ]
 		})
 	}
 }
+
+func TestBracketsFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name: "MinSpacesInside",
+			lint: Brackets{
+				MinSpacesInside: 1,
+				MaxSpacesInside: 1,
+			},
+			input:    "object: [1, 2, abc]\n",
+			expected: "object: [ 1, 2, abc ]\n",
+		},
+		{
+			name: "MaxSpacesInside",
+			lint: Brackets{
+				MinSpacesInside: 0,
+				MaxSpacesInside: 0,
+			},
+			input:    "object: [   1, [ 2, 3 ], \"abc\"  ]\n",
+			expected: "object: [1, [2, 3], \"abc\"]\n",
+		},
+		{
+			name: "MinSpacesInsideEmpty",
+			lint: Brackets{
+				MinSpacesInsideEmpty: 1,
+				MaxSpacesInsideEmpty: 1,
+			},
+			input:    "object: []\n",
+			expected: "object: [ ]\n",
+		},
+		{
+			name: "MaxSpacesInsideEmpty",
+			lint: Brackets{
+				MinSpacesInsideEmpty: 0,
+				MaxSpacesInsideEmpty: 0,
+			},
+			input:    "object: [    ]\n",
+			expected: "object: []\n",
+		},
+	})
+}
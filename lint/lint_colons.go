@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"iter"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+var (
+	ErrColonsTooManySpacesBefore            = newError("too many spaces before colon")
+	ErrColonsTooManySpacesAfter             = newError("too many spaces after colon")
+	ErrColonsTooManySpacesAfterQuestionMark = newError("too many spaces after question mark")
+)
+
+// Colons checks the spacing around the colons of mappings and after the
+// question marks of explicit keys. A negative maximum disables its check.
+type Colons struct {
+	MaxSpacesBefore int
+	MaxSpacesAfter  int
+}
+
+func (c Colons) Name() string {
+	return "colons"
+}
+
+func (c Colons) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		if c.MaxSpacesBefore >= 0 {
+			if !c.checkSpacesBefore(ctx, yield) {
+				return
+			}
+		}
+
+		if c.MaxSpacesAfter >= 0 {
+			if !c.checkSpacesAfter(ctx, yield) {
+				return
+			}
+		}
+	}
+}
+
+func (c Colons) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func (c Colons) checkSpacesBefore(ctx tokenContext, yield func(Problem) bool) bool {
+	if ctx.lastToken == nil || ctx.currentToken.Type != token.MappingValueType {
+		return true
+	}
+
+	spaces := spacesBetween(ctx.lastToken, ctx.currentToken)
+	if spaces < 0 {
+		return true
+	}
+
+	// An alias used as a key needs a space before the colon, which would
+	// otherwise be part of its name.
+	if spaces == 1 && ctx.lastToken.Prev != nil && ctx.lastToken.Prev.Type == token.AliasType {
+		return true
+	}
+
+	if spaces > c.MaxSpacesBefore {
+		problem := spacesProblem(ctx.lastToken, spaces, ErrColonsTooManySpacesBefore).
+			withFix(respace(ctx.lastToken, spaces, c.MaxSpacesBefore))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Colons) checkSpacesAfter(ctx tokenContext, yield func(Problem) bool) bool {
+	if ctx.nextToken == nil || ctx.nextToken.Type == token.CommentType {
+		return true
+	}
+
+	err := ErrColonsTooManySpacesAfter
+	switch ctx.currentToken.Type {
+	case token.MappingValueType:
+	case token.MappingKeyType:
+		err = ErrColonsTooManySpacesAfterQuestionMark
+	default:
+		return true
+	}
+
+	spaces := spacesBetween(ctx.currentToken, ctx.nextToken)
+	if spaces < 0 {
+		return true
+	}
+
+	if spaces > c.MaxSpacesAfter {
+		problem := spacesProblem(ctx.currentToken, spaces, err).
+			withFix(respace(ctx.currentToken, spaces, c.MaxSpacesAfter))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColons(t *testing.T) {
+	defaults := Colons{MaxSpacesBefore: 0, MaxSpacesAfter: 1}
+
+	tests := []struct {
+		name        string
+		lint        Linter
+		input       string
+		expectedErr error
+	}{
+		{
+			name: "Pass",
+			lint: defaults,
+			input: `
+key: value
+nested:
+  list: [a, b]
+  map: {a: 1, b: 2}
+empty:
+`,
+			expectedErr: nil,
+		},
+		{
+			name:        "MaxSpacesAfter Trailing Spaces",
+			lint:        defaults,
+			input:       "replicas: 3  \nimage: nginx\t\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "MaxSpacesBefore Fail",
+			lint:        defaults,
+			input:       "key : value\n",
+			expectedErr: ErrColonsTooManySpacesBefore,
+		},
+		{
+			name:        "MaxSpacesAfter Fail",
+			lint:        defaults,
+			input:       "key:  value\n",
+			expectedErr: ErrColonsTooManySpacesAfter,
+		},
+		{
+			name:        "Flow Mapping Before Fail",
+			lint:        defaults,
+			input:       "map: {a : 1}\n",
+			expectedErr: ErrColonsTooManySpacesBefore,
+		},
+		{
+			name:        "Flow Mapping After Fail",
+			lint:        defaults,
+			input:       "map: {a:   1}\n",
+			expectedErr: ErrColonsTooManySpacesAfter,
+		},
+		{
+			name:        "Quoted Key Fail",
+			lint:        defaults,
+			input:       "\"key\" : value\n",
+			expectedErr: ErrColonsTooManySpacesBefore,
+		},
+		{
+			name:        "Explicit Key Pass",
+			lint:        defaults,
+			input:       "? key\n: value\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Explicit Key Fail",
+			lint:        defaults,
+			input:       "?   key\n: value\n",
+			expectedErr: ErrColonsTooManySpacesAfterQuestionMark,
+		},
+		{
+			name:        "Explicit Value Fail",
+			lint:        defaults,
+			input:       "? key\n:   value\n",
+			expectedErr: ErrColonsTooManySpacesAfter,
+		},
+		{
+			name:        "Colons In Scalars Pass",
+			lint:        defaults,
+			input:       "url: http://example.com:80\nquoted: \"a : b\"\nsingle: 'a  :  b'\ntime: 12:30\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Alias Key Pass",
+			lint:        defaults,
+			input:       "base: &anchor a\n*anchor : b\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Alias Key Fail",
+			lint:        defaults,
+			input:       "base: &anchor a\n*anchor  : b\n",
+			expectedErr: ErrColonsTooManySpacesBefore,
+		},
+		{
+			name:        "Comment After Colon Pass",
+			lint:        defaults,
+			input:       "key:   # comment\n  nested: value\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Aligned Values Pass",
+			lint:        Colons{MaxSpacesBefore: 0, MaxSpacesAfter: -1},
+			input:       "a:     1\nlong: 2\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Disabled Before Pass",
+			lint:        Colons{MaxSpacesBefore: -1, MaxSpacesAfter: 1},
+			input:       "a    : 1\n",
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			problem := Lint([]byte(tt.input), tt.lint)
+			if problem == nil {
+				assert.Nil(t, tt.expectedErr, "expected problem but got nil")
+				return
+			}
+
+			assert.ErrorIs(t, problem.Error, tt.expectedErr)
+		})
+	}
+}
+
+func TestColonsRange(t *testing.T) {
+	problem := Lint([]byte("key:   value\n"), Colons{MaxSpacesAfter: 1})
+	assert.NotNil(t, problem)
+	assert.Equal(t, []int{1, 5, 1, 8}, []int{problem.Line, problem.Column, problem.EndLine, problem.EndColumn})
+}
+
+func TestColonsFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name:     "Spaces Before And After",
+			lint:     Colons{MaxSpacesBefore: 0, MaxSpacesAfter: 1},
+			input:    "a  :   1\nmap: {b :  2, c: 3}\n?    key\n:  value\n",
+			expected: "a: 1\nmap: {b: 2, c: 3}\n? key\n: value\n",
+		},
+		{
+			name:     "Trailing Spaces",
+			lint:     Colons{MaxSpacesBefore: 0, MaxSpacesAfter: 1},
+			input:    "replicas:  3  \nimage:  nginx   \n",
+			expected: "replicas: 3  \nimage: nginx   \n",
+		},
+	})
+
+	t.Run("With TrailingSpaces", func(t *testing.T) {
+		input := "replicas: 3  \nimage:  nginx   \nkey:  value  \n"
+		expected := "replicas: 3\nimage: nginx\nkey: value\n"
+
+		fixed, problems := Fix([]byte(input), Colons{MaxSpacesAfter: 1}, TrailingSpaces{})
+		assert.Equal(t, expected, string(fixed))
+		assert.Empty(t, problems)
+	})
+}
@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"iter"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+var (
+	ErrCommasTooManySpacesBefore = newError("too many spaces before comma")
+	ErrCommasTooFewSpacesAfter   = newError("too few spaces after comma")
+	ErrCommasTooManySpacesAfter  = newError("too many spaces after comma")
+)
+
+// Commas checks the spacing around the commas of flow collections. Commas
+// at the start or end of a line are only checked on the side that is on the
+// same line as the neighbouring token. A negative maximum disables its check.
+type Commas struct {
+	MaxSpacesBefore int
+	MinSpacesAfter  int
+	MaxSpacesAfter  int
+}
+
+func (c Commas) Name() string {
+	return "commas"
+}
+
+func (c Commas) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		if ctx.currentToken.Type != token.CollectEntryType {
+			return
+		}
+
+		if !c.checkSpacesBefore(ctx, yield) {
+			return
+		}
+		if !c.checkSpacesAfter(ctx, yield) {
+			return
+		}
+	}
+}
+
+func (c Commas) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func (c Commas) checkSpacesBefore(ctx tokenContext, yield func(Problem) bool) bool {
+	if ctx.lastToken == nil || c.MaxSpacesBefore < 0 {
+		return true
+	}
+
+	spaces := spacesBetween(ctx.lastToken, ctx.currentToken)
+	if spaces < 0 {
+		return true
+	}
+
+	if spaces > c.MaxSpacesBefore {
+		problem := spacesProblem(ctx.lastToken, spaces, ErrCommasTooManySpacesBefore).
+			withFix(respace(ctx.lastToken, spaces, c.MaxSpacesBefore))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Commas) checkSpacesAfter(ctx tokenContext, yield func(Problem) bool) bool {
+	if ctx.nextToken == nil || ctx.nextToken.Type == token.CommentType {
+		return true
+	}
+
+	spaces := spacesBetween(ctx.currentToken, ctx.nextToken)
+	if spaces < 0 {
+		return true
+	}
+
+	if spaces < c.MinSpacesAfter {
+		problem := tokenProblem(ctx.currentToken, ErrCommasTooFewSpacesAfter).
+			withFix(respace(ctx.currentToken, spaces, c.MinSpacesAfter))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	if c.MaxSpacesAfter >= 0 && spaces > c.MaxSpacesAfter {
+		problem := spacesProblem(ctx.currentToken, spaces, ErrCommasTooManySpacesAfter).
+			withFix(respace(ctx.currentToken, spaces, c.MaxSpacesAfter))
+		if !yield(problem) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommas(t *testing.T) {
+	defaults := Commas{MaxSpacesBefore: 0, MinSpacesAfter: 1, MaxSpacesAfter: 1}
+
+	tests := []struct {
+		name        string
+		lint        Linter
+		input       string
+		expectedErr error
+	}{
+		{
+			name:        "Pass",
+			lint:        defaults,
+			input:       "list: [a, b, c]\nmap: {a: 1, b: 2}\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "MaxSpacesBefore Fail",
+			lint:        defaults,
+			input:       "list: [a ,b]\n",
+			expectedErr: ErrCommasTooManySpacesBefore,
+		},
+		{
+			name:        "MinSpacesAfter Fail",
+			lint:        defaults,
+			input:       "list: [a,b]\n",
+			expectedErr: ErrCommasTooFewSpacesAfter,
+		},
+		{
+			name:        "MaxSpacesAfter Fail",
+			lint:        defaults,
+			input:       "map: {a: 1,   b: 2}\n",
+			expectedErr: ErrCommasTooManySpacesAfter,
+		},
+		{
+			name:        "Comma At End Of Line Pass",
+			lint:        defaults,
+			input:       "list: [\n  a,\n  b,\n]\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Comma At Start Of Line Pass",
+			lint:        defaults,
+			input:       "list: [\n  a\n  , b\n  , c\n]\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Comma At Start Of Line Fail",
+			lint:        defaults,
+			input:       "list: [\n  a\n  ,   b\n]\n",
+			expectedErr: ErrCommasTooManySpacesAfter,
+		},
+		{
+			name:        "Comment After Comma Pass",
+			lint:        defaults,
+			input:       "list: [a,   # comment\n  b]\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Commas In Scalars Pass",
+			lint:        defaults,
+			input:       "plain: a ,b\nquoted: \"a ,b\"\nlist: ['a ,b', c]\n",
+			expectedErr: nil,
+		},
+		{
+			name:        "Disabled Maximums Pass",
+			lint:        Commas{MaxSpacesBefore: -1, MinSpacesAfter: 0, MaxSpacesAfter: -1},
+			input:       "list: [a   ,b,    c]\n",
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			problem := Lint([]byte(tt.input), tt.lint)
+			if problem == nil {
+				assert.Nil(t, tt.expectedErr, "expected problem but got nil")
+				return
+			}
+
+			assert.ErrorIs(t, problem.Error, tt.expectedErr)
+		})
+	}
+}
+
+func TestCommasFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name:     "Spacing",
+			lint:     Commas{MaxSpacesBefore: 0, MinSpacesAfter: 1, MaxSpacesAfter: 1},
+			input:    "list: [a ,b,    c]\nmap: {a: 1  ,b: 2}\nmulti: [\n  a ,\n  b\n  ,c\n]\n",
+			expected: "list: [a, b, c]\nmap: {a: 1, b: 2}\nmulti: [\n  a,\n  b\n  , c\n]\n",
+		},
+	})
+}
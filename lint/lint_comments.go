@@ -1,19 +1,22 @@
 package lint
 
 import (
-	"errors"
 	"iter"
 
 	"github.com/goccy/go-yaml/token"
 )
 
-var ErrCommentRequireStartingSpace = errors.New("comment must start with a space")
+var ErrCommentRequireStartingSpace = newError("comment must start with a space")
 
 type Comments struct {
 	RequireStartingSpace bool
 	IgnoreShebangs       bool
 }
 
+func (c Comments) Name() string {
+	return "comments"
+}
+
 func (c Comments) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {
 		if c.RequireStartingSpace {
@@ -40,11 +43,11 @@ func (c Comments) checkStartingSpace(ctx tokenContext, yield func(Problem) bool)
 	}
 
 	if ctx.currentToken.Value[0] != ' ' {
-		problem := problem(
+		problem := tokenProblem(ctx.currentToken, ErrCommentRequireStartingSpace).withFix(insert(
 			ctx.currentToken.Position.Line,
-			ctx.currentToken.Position.Column,
-			ErrCommentRequireStartingSpace,
-		)
+			ctx.currentToken.Position.Column+1,
+			" ",
+		))
 		if !yield(problem) {
 			return nil
 		}
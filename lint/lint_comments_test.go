@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		name        string
+		lint        Linter
+		input       string
+		expectedErr error
+	}{
+		{
+			name: "RequireStartingSpace Pass",
+			lint: Comments{
+				RequireStartingSpace: true,
+			},
+			input: `
+---
+# comment
+key: value # comment
+`,
+			expectedErr: nil,
+		},
+		{
+			name: "RequireStartingSpace Fail",
+			lint: Comments{
+				RequireStartingSpace: true,
+			},
+			input: `
+---
+key: value #comment
+`,
+			expectedErr: ErrCommentRequireStartingSpace,
+		},
+		{
+			name: "IgnoreShebangs Pass",
+			lint: Comments{
+				RequireStartingSpace: true,
+				IgnoreShebangs:       true,
+			},
+			input: `#!/usr/bin/env yamllintx
+key: value
+`,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			problem := Lint([]byte(tt.input), tt.lint)
+			if problem == nil {
+				assert.Nil(t, tt.expectedErr, "expected problem but got nil")
+				return
+			}
+
+			assert.ErrorIs(t, problem.Error, tt.expectedErr)
+		})
+	}
+}
+
+func TestCommentsFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name: "RequireStartingSpace",
+			lint: Comments{
+				RequireStartingSpace: true,
+			},
+			input:    "#comment\nkey: value #comment\n",
+			expected: "# comment\nkey: value # comment\n",
+		},
+		{
+			name: "RequireStartingSpace Multibyte",
+			lint: Comments{
+				RequireStartingSpace: true,
+			},
+			input:    "ключ: значение #комментарий\n",
+			expected: "ключ: значение # комментарий\n",
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"iter"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+var (
+	ErrDocumentEndMissing   = newError(`missing document end "..."`)
+	ErrDocumentEndForbidden = newError(`found forbidden document end "..."`)
+)
+
+// DocumentEnd requires every document to end with "..." if Present is set,
+// and forbids the marker otherwise.
+type DocumentEnd struct {
+	Present bool
+}
+
+func (d DocumentEnd) Name() string {
+	return "document-end"
+}
+
+func (d DocumentEnd) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		tk := ctx.currentToken
+
+		if !d.Present {
+			if tk.Type == token.DocumentEndType {
+				yield(tokenProblem(tk, ErrDocumentEndForbidden))
+			}
+			return
+		}
+
+		// A document that is followed by another must end before the next
+		// one starts.
+		if tk.Type == token.DocumentHeaderType {
+			prev := significantBefore(tk)
+			if prev != nil && prev.Type != token.DocumentEndType {
+				if !yield(tokenProblem(tk, ErrDocumentEndMissing)) {
+					return
+				}
+			}
+		}
+
+		// The last document must end before the end of the stream, where
+		// the problem is reported. Earlier lines may already have been
+		// yielded, see Linter.
+		if ctx.nextToken == nil {
+			last := tk
+			if last.Type == token.CommentType || isDirective(last) {
+				last = significantBefore(last)
+			}
+
+			if last != nil && last.Type != token.DocumentEndType {
+				line, column := tokenEnd(tk)
+				yield(problem(line, column, ErrDocumentEndMissing))
+			}
+		}
+	}
+}
+
+func (d DocumentEnd) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
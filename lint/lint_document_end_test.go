@@ -0,0 +1,73 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		lint     Linter
+		input    string
+		expected []string
+	}{
+		{
+			name:  "Present Pass",
+			lint:  DocumentEnd{Present: true},
+			input: "---\na: 1\n...\n# trailing comment\n",
+		},
+		{
+			name:     "Present Fail",
+			lint:     DocumentEnd{Present: true},
+			input:    "---\na: 1\n# trailing comment\n",
+			expected: []string{`3:19 missing document end "..."`},
+		},
+		{
+			name:  "Multiple Documents Pass",
+			lint:  DocumentEnd{Present: true},
+			input: "---\na: 1\n...\n%YAML 1.2\n---\nb: 2\n...\n",
+		},
+		{
+			name:     "Multiple Documents Fail",
+			lint:     DocumentEnd{Present: true},
+			input:    "---\na: 1\n---\nb: 2\n...\n---\nc: 3\n",
+			expected: []string{`3:1 missing document end "..."`, `7:5 missing document end "..."`},
+		},
+		{
+			name:  "Empty Pass",
+			lint:  DocumentEnd{Present: true},
+			input: "# only a comment\n",
+		},
+		{
+			name:  "Forbidden Pass",
+			lint:  DocumentEnd{Present: false},
+			input: "---\na: 1\n",
+		},
+		{
+			name:     "Forbidden Fail",
+			lint:     DocumentEnd{Present: false},
+			input:    "---\na: 1\n...\n---\nb: 2\n...\n",
+			expected: []string{`3:1 found forbidden document end "..."`, `6:1 found forbidden document end "..."`},
+		},
+	}
+
+	t.Run("Order", func(t *testing.T) {
+		const input = "a: 1\nb: 2   \n# x\n\n\n# y\n# z\n"
+
+		assert.Equal(t, []string{
+			"2:5 trailing spaces are forbidden",
+			"5:1 too many blank lines (2 > 0)",
+			`7:4 missing document end "..."`,
+		}, problemPositions(input, DocumentEnd{Present: true}, TrailingSpaces{}, EmptyLines{}))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, problemPositions(tt.input, tt.lint))
+		})
+	}
+}
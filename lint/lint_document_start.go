@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"iter"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+var (
+	ErrDocumentStartMissing   = newError(`missing document start "---"`)
+	ErrDocumentStartForbidden = newError(`found forbidden document start "---"`)
+)
+
+// DocumentStart requires every document to start with "---" if Present is
+// set, and forbids the marker otherwise.
+type DocumentStart struct {
+	Present bool
+}
+
+func (d DocumentStart) Name() string {
+	return "document-start"
+}
+
+func (d DocumentStart) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		tk := ctx.currentToken
+
+		if !d.Present {
+			if tk.Type == token.DocumentHeaderType {
+				yield(tokenProblem(tk, ErrDocumentStartForbidden))
+			}
+			return
+		}
+
+		if !isDocumentContent(tk) {
+			return
+		}
+
+		// Only the first content of a document is checked, which follows
+		// the start of the stream or the end of the previous document.
+		prev := significantBefore(tk)
+		if prev != nil && prev.Type != token.DocumentEndType {
+			return
+		}
+
+		problem := tokenProblem(tk, ErrDocumentStartMissing).
+			withFix(insert(tk.Position.Line, 1, "---\n"))
+		yield(problem)
+	}
+}
+
+func (d DocumentStart) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+// isDocumentContent reports whether the token belongs to the content of a
+// document, rather than being a comment, a directive or a document marker.
+func isDocumentContent(tk *token.Token) bool {
+	switch tk.Type {
+	case token.CommentType, token.DocumentHeaderType, token.DocumentEndType:
+		return false
+	}
+	return !isDirective(tk)
+}
+
+// isDirective reports whether the token is part of a directive such as
+// "%YAML 1.2", which spans the rest of its line.
+func isDirective(tk *token.Token) bool {
+	for t := tk; t != nil && t.Position.Line == tk.Position.Line; t = t.Prev {
+		if t.Type == token.DirectiveType {
+			return true
+		}
+	}
+	return false
+}
+
+// significantBefore returns the closest token before tk that is not a
+// comment or part of a directive, or nil at the start of the stream.
+func significantBefore(tk *token.Token) *token.Token {
+	for prev := tk.Prev; prev != nil; prev = prev.Prev {
+		if prev.Type != token.CommentType && !isDirective(prev) {
+			return prev
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		lint     Linter
+		input    string
+		expected []string
+	}{
+		{
+			name:  "Present Pass",
+			lint:  DocumentStart{Present: true},
+			input: "# comment\n---\na: 1\n",
+		},
+		{
+			name:     "Present Fail",
+			lint:     DocumentStart{Present: true},
+			input:    "# comment\na: 1\n",
+			expected: []string{`2:1 missing document start "---"`},
+		},
+		{
+			name:  "Directives Pass",
+			lint:  DocumentStart{Present: true},
+			input: "%YAML 1.2\n---\na: 1\n",
+		},
+		{
+			name:  "Multiple Documents Pass",
+			lint:  DocumentStart{Present: true},
+			input: "---\na: 1\n...\n---\nb: 2\n--- !!map\nc: 3\n",
+		},
+		{
+			name:     "Multiple Documents Fail",
+			lint:     DocumentStart{Present: true},
+			input:    "a: 1\n---\nb: 2\n...\nc: 3\n",
+			expected: []string{`1:1 missing document start "---"`, `5:1 missing document start "---"`},
+		},
+		{
+			name:  "Empty Pass",
+			lint:  DocumentStart{Present: true},
+			input: "# only a comment\n",
+		},
+		{
+			name:  "Forbidden Pass",
+			lint:  DocumentStart{Present: false},
+			input: "a: 1\n",
+		},
+		{
+			name:     "Forbidden Fail",
+			lint:     DocumentStart{Present: false},
+			input:    "---\na: 1\n---\nb: 2\n",
+			expected: []string{`1:1 found forbidden document start "---"`, `3:1 found forbidden document start "---"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, problemPositions(tt.input, tt.lint))
+		})
+	}
+}
+
+func TestDocumentStartFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name:     "Present",
+			lint:     DocumentStart{Present: true},
+			input:    "# comment\na: 1\n...\nb: 2\n",
+			expected: "# comment\n---\na: 1\n...\n---\nb: 2\n",
+		},
+	})
+}
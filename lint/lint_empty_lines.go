@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"fmt"
+	"iter"
+)
+
+var ErrEmptyLinesTooMany = newError("too many blank lines")
+
+// EmptyLines limits the number of consecutive blank lines. Runs at the start
+// and at the end of the source are limited by MaxStart and MaxEnd instead of
+// Max. Blank lines inside block scalars are content and never counted.
+type EmptyLines struct {
+	Max      int
+	MaxStart int
+	MaxEnd   int
+}
+
+func (e EmptyLines) Name() string {
+	return "empty-lines"
+}
+
+func (e EmptyLines) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func (e EmptyLines) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		line := ctx.currentLineNumber
+		if !emptyLine(ctx, line) {
+			return
+		}
+
+		// Only the last blank line of a run is checked.
+		if line < ctx.totalLines() && emptyLine(ctx, line+1) {
+			return
+		}
+
+		first := line
+		for first > 1 && emptyLine(ctx, first-1) {
+			first--
+		}
+		blank := line - first + 1
+
+		limit := e.Max
+		if first == 1 {
+			limit = e.MaxStart
+		}
+		if line == ctx.totalLines() {
+			// A source made of a single line break is left alone.
+			if ctx.totalLines() == 1 {
+				return
+			}
+			limit = e.MaxEnd
+		}
+
+		if blank <= limit {
+			return
+		}
+
+		problem := problem(line, 1, fmt.Errorf("%w (%d > %d)", ErrEmptyLinesTooMany, blank, limit))
+		problem = problem.withFix(replace(first+limit, 1, line+1, 1, ""))
+
+		yield(problem)
+	}
+}
+
+func emptyLine(ctx lineContext, n int) bool {
+	return ctx.line(n) == "" && !ctx.inBlockScalar(n)
+}
@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyLines(t *testing.T) {
+	defaults := EmptyLines{Max: 2}
+
+	tests := []struct {
+		name     string
+		lint     Linter
+		input    string
+		expected []string
+	}{
+		{
+			name:  "Pass",
+			lint:  defaults,
+			input: "a: 1\n\n\nb: 2\n",
+		},
+		{
+			name:     "Too Many",
+			lint:     defaults,
+			input:    "a: 1\n\n\n\nb: 2\n\n\n\n\nc: 3\n",
+			expected: []string{"4:1 too many blank lines (3 > 2)", "9:1 too many blank lines (4 > 2)"},
+		},
+		{
+			name:     "Start",
+			lint:     defaults,
+			input:    "\na: 1\n",
+			expected: []string{"1:1 too many blank lines (1 > 0)"},
+		},
+		{
+			name:     "End",
+			lint:     defaults,
+			input:    "a: 1\n\n",
+			expected: []string{"2:1 too many blank lines (1 > 0)"},
+		},
+		{
+			name:  "Start And End Allowed",
+			lint:  EmptyLines{Max: 2, MaxStart: 1, MaxEnd: 2},
+			input: "\na: 1\n\n\n",
+		},
+		{
+			name:  "Single Line Break",
+			lint:  defaults,
+			input: "\n",
+		},
+		{
+			name:  "Whitespace Is Not Blank",
+			lint:  EmptyLines{},
+			input: "a: 1\n  \nb: 2\n",
+		},
+		{
+			name:  "Block Scalar",
+			lint:  EmptyLines{},
+			input: "a: |\n  x\n\n\n\n  y\nb: |+\n  z\n\n",
+		},
+		{
+			name:  "Block Scalar With Header Comment",
+			lint:  EmptyLines{},
+			input: "a: | # comment\n  x\n\n\n\n  y\nb: |+ # comment\n  z\n\n",
+		},
+		{
+			name:     "After Block Scalar",
+			lint:     EmptyLines{},
+			input:    "a: |\n  x\n\nb: >-\n\n  y\n\n",
+			expected: []string{"3:1 too many blank lines (1 > 0)", "7:1 too many blank lines (1 > 0)"},
+		},
+		{
+			name:     "CRLF",
+			lint:     defaults,
+			input:    "a: 1\r\n\r\n\r\n\r\nb: 2\r\n",
+			expected: []string{"4:1 too many blank lines (3 > 2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, problemPositions(tt.input, tt.lint))
+		})
+	}
+}
+
+func TestEmptyLinesFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name:     "Middle",
+			lint:     EmptyLines{Max: 1},
+			input:    "a: 1\n\n\n\nb: 2\n",
+			expected: "a: 1\n\nb: 2\n",
+		},
+		{
+			name:     "Start And End",
+			lint:     EmptyLines{Max: 2},
+			input:    "\n\na: 1\n\n\n",
+			expected: "a: 1\n",
+		},
+		{
+			name:     "Block Scalar With Header Comment",
+			lint:     EmptyLines{},
+			input:    "a: | # comment\n  x\n\n\n  y\n\nb: 1\n",
+			expected: "a: | # comment\n  x\n\n\n  y\nb: 1\n",
+		},
+		{
+			name:     "CRLF",
+			lint:     EmptyLines{},
+			input:    "a: 1\r\n\r\nb: 2\r\n",
+			expected: "a: 1\r\nb: 2\r\n",
+		},
+	})
+}
@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"iter"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+var (
+	ErrEmptyValueInBlockMapping  = newError("empty value in block mapping")
+	ErrEmptyValueInFlowMapping   = newError("empty value in flow mapping")
+	ErrEmptyValueInBlockSequence = newError("empty value in block sequence")
+)
+
+// EmptyValues forbids implicit null values, such as a key with nothing after
+// its colon, in the kinds of collection that are enabled. Explicit nulls like
+// "null" and "~" are allowed.
+type EmptyValues struct {
+	ForbidInBlockMappings  bool
+	ForbidInFlowMappings   bool
+	ForbidInBlockSequences bool
+}
+
+func (e EmptyValues) Name() string {
+	return "empty-values"
+}
+
+func (e EmptyValues) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func (e EmptyValues) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func (e EmptyValues) checkFile(file *ast.File) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		v := &emptyValuesVisitor{EmptyValues: e}
+		for _, doc := range file.Docs {
+			ast.Walk(v, doc)
+		}
+
+		for _, problem := range v.problems {
+			if !yield(problem) {
+				return
+			}
+		}
+	}
+}
+
+type emptyValuesVisitor struct {
+	EmptyValues
+	problems []Problem
+}
+
+func (v *emptyValuesVisitor) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.MappingNode:
+		forbid, err := v.ForbidInBlockMappings, ErrEmptyValueInBlockMapping
+		if node.IsFlowStyle {
+			forbid, err = v.ForbidInFlowMappings, ErrEmptyValueInFlowMapping
+		}
+
+		if forbid {
+			for _, value := range node.Values {
+				v.checkMappingValue(value, err)
+			}
+		}
+	case *ast.SequenceNode:
+		if node.IsFlowStyle || !v.ForbidInBlockSequences {
+			break
+		}
+
+		for _, value := range node.Values {
+			if implicitNull(value) {
+				// The parser places an implicit null right after the
+				// hyphen of its entry.
+				pos := value.GetToken().Position
+				v.problems = append(v.problems, problem(pos.Line, pos.Column-1, ErrEmptyValueInBlockSequence))
+			}
+		}
+	}
+
+	return v
+}
+
+// checkMappingValue reports a pair with an implicit null value, from the
+// start of its key up to its colon.
+func (v *emptyValuesVisitor) checkMappingValue(value *ast.MappingValueNode, err error) {
+	// A key without a colon, like "? key" or a bare key in a flow mapping,
+	// has no value to leave empty.
+	colon := value.Start
+	if colon.Type != token.MappingValueType || !implicitNull(value.Value) {
+		return
+	}
+
+	key := value.Key.GetToken().Position
+	v.problems = append(v.problems, problemRange(
+		key.Line,
+		key.Column,
+		colon.Position.Line,
+		colon.Position.Column+1,
+		err,
+	))
+}
+
+// implicitNull reports whether the node is a null the parser filled in for a
+// missing value. Unlike an explicit null, its token is not linked to the
+// tokens of the source.
+func implicitNull(node ast.Node) bool {
+	null, ok := node.(*ast.NullNode)
+	return ok && null.GetToken().Prev == nil
+}
@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyValues(t *testing.T) {
+	all := EmptyValues{
+		ForbidInBlockMappings:  true,
+		ForbidInFlowMappings:   true,
+		ForbidInBlockSequences: true,
+	}
+
+	tests := []struct {
+		name     string
+		lint     Linter
+		input    string
+		expected []string
+	}{
+		{
+			name:  "Explicit Nulls",
+			lint:  all,
+			input: "a: null\nb: ~\nc: {d: null}\ne:\n  - ~\n  - \"\"\n",
+		},
+		{
+			name:     "Block Mapping",
+			lint:     all,
+			input:    "image:\ntag: latest\nnested:\n  key:\n",
+			expected: []string{"1:1 empty value in block mapping", "4:3 empty value in block mapping"},
+		},
+		{
+			name:     "Block Mapping With Comment",
+			lint:     all,
+			input:    "image: # set by the template\ntag: latest\n",
+			expected: []string{"1:1 empty value in block mapping"},
+		},
+		{
+			name:     "Explicit Key",
+			lint:     all,
+			input:    "? key\n:\n? other\n",
+			expected: []string{"1:1 empty value in block mapping"},
+		},
+		{
+			name:     "Flow Mapping",
+			lint:     all,
+			input:    "a: {b: , c: 1, d}\n",
+			expected: []string{"1:5 empty value in flow mapping"},
+		},
+		{
+			name:     "Flow Mapping In Flow Sequence",
+			lint:     all,
+			input:    "a: [{b: }, c]\n",
+			expected: []string{"1:6 empty value in flow mapping"},
+		},
+		{
+			name:     "Block Sequence",
+			lint:     all,
+			input:    "list:\n  - a\n  -\n  - b:\n",
+			expected: []string{"3:3 empty value in block sequence", "4:5 empty value in block mapping"},
+		},
+		{
+			name:     "Multiple Documents",
+			lint:     all,
+			input:    "---\na:\n---\nb: 1\nc:\n",
+			expected: []string{"2:1 empty value in block mapping", "5:1 empty value in block mapping"},
+		},
+		{
+			name:  "Disable Line",
+			lint:  all,
+			input: "a: # yamllint disable-line rule:empty-values\nb: 1\n",
+		},
+		{
+			name:  "Disabled",
+			lint:  EmptyValues{},
+			input: "a:\nb: {c: }\nd:\n  -\n",
+		},
+		{
+			name:     "Only Flow Mappings",
+			lint:     EmptyValues{ForbidInFlowMappings: true},
+			input:    "a:\nb: {c: }\nd:\n  -\n",
+			expected: []string{"2:5 empty value in flow mapping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, problemPositions(tt.input, tt.lint))
+		})
+	}
+}
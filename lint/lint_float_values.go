@@ -0,0 +1,98 @@
+package lint
+
+import (
+	"fmt"
+	"iter"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+var (
+	ErrFloatNaN                = newError("forbidden not a number value")
+	ErrFloatInfinity           = newError("forbidden infinite value")
+	ErrFloatScientificNotation = newError("forbidden scientific notation")
+	ErrFloatMissingNumeral     = newError("forbidden decimal missing 0 prefix")
+)
+
+var (
+	floatNaNPattern                = regexp.MustCompile(`^(\.nan|\.NaN|\.NAN)$`)
+	floatInfinityPattern           = regexp.MustCompile(`^[-+]?(\.inf|\.Inf|\.INF)$`)
+	floatScientificNotationPattern = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)$`)
+	floatMissingNumeralPattern     = regexp.MustCompile(`^[-+]?(\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+)
+
+// FloatValues restricts how plain scalars may spell floating point numbers.
+// Quoted and tagged scalars are left alone.
+type FloatValues struct {
+	RequireNumeralBeforeDecimal bool
+	ForbidScientificNotation    bool
+	ForbidNaN                   bool
+	ForbidInf                   bool
+}
+
+func (f FloatValues) Name() string {
+	return "float-values"
+}
+
+func (f FloatValues) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		tk := ctx.currentToken
+		if !plainFloat(ctx) {
+			return
+		}
+
+		if f.ForbidNaN && floatNaNPattern.MatchString(tk.Value) {
+			if !yield(tokenProblem(tk, fmt.Errorf("%w %q", ErrFloatNaN, tk.Value))) {
+				return
+			}
+		}
+
+		if f.ForbidInf && floatInfinityPattern.MatchString(tk.Value) {
+			if !yield(tokenProblem(tk, fmt.Errorf("%w %q", ErrFloatInfinity, tk.Value))) {
+				return
+			}
+		}
+
+		if f.ForbidScientificNotation && floatScientificNotationPattern.MatchString(tk.Value) {
+			if !yield(tokenProblem(tk, fmt.Errorf("%w %q", ErrFloatScientificNotation, tk.Value))) {
+				return
+			}
+		}
+
+		if f.RequireNumeralBeforeDecimal && floatMissingNumeralPattern.MatchString(tk.Value) {
+			problem := tokenProblem(tk, fmt.Errorf("%w %q", ErrFloatMissingNumeral, tk.Value))
+			dot := strings.IndexByte(tk.Value, '.')
+			problem = problem.withFix(insert(problem.Line, problem.Column+dot, "0"))
+
+			if !yield(problem) {
+				return
+			}
+		}
+	}
+}
+
+func (f FloatValues) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+// plainFloat reports whether the current token is an untagged plain scalar
+// that may be a float. The lexer leaves some floats of the core schema, like
+// 1e3 and +.INF, as plain strings, so those are checked against the patterns
+// too. Quoted scalars have token types of their own.
+func plainFloat(ctx tokenContext) bool {
+	if ctx.lastToken != nil {
+		switch ctx.lastToken.Type {
+		case token.TagType, token.LiteralType, token.FoldedType:
+			return false
+		}
+	}
+
+	switch ctx.currentToken.Type {
+	case token.FloatType, token.InfinityType, token.NanType, token.StringType:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,92 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloatValues(t *testing.T) {
+	all := FloatValues{
+		RequireNumeralBeforeDecimal: true,
+		ForbidScientificNotation:    true,
+		ForbidNaN:                   true,
+		ForbidInf:                   true,
+	}
+
+	tests := []struct {
+		name     string
+		lint     Linter
+		input    string
+		expected []string
+	}{
+		{
+			name:  "Pass",
+			lint:  all,
+			input: "a: 0.5\nb: -1.25\nc: 10\nd: 1.\ne: nan\nf: version.5\n",
+		},
+		{
+			name:     "Numeral Before Decimal",
+			lint:     all,
+			input:    "a: .5\nb: -.5\n.5: key\nc: [.5, 1]\n",
+			expected: []string{`1:4 forbidden decimal missing 0 prefix ".5"`, `2:4 forbidden decimal missing 0 prefix "-.5"`, `3:1 forbidden decimal missing 0 prefix ".5"`, `4:5 forbidden decimal missing 0 prefix ".5"`},
+		},
+		{
+			name:     "Scientific Notation",
+			lint:     all,
+			input:    "a: 1e3\nb: 1.5E-3\nc: .5e3\n",
+			expected: []string{`1:4 forbidden scientific notation "1e3"`, `2:4 forbidden scientific notation "1.5E-3"`, `3:4 forbidden decimal missing 0 prefix ".5e3"`, `3:4 forbidden scientific notation ".5e3"`},
+		},
+		{
+			name:     "NaN",
+			lint:     all,
+			input:    "a: .nan\nb: .NaN\nc: .NAN\n",
+			expected: []string{`1:4 forbidden not a number value ".nan"`, `2:4 forbidden not a number value ".NaN"`, `3:4 forbidden not a number value ".NAN"`},
+		},
+		{
+			name:     "Infinity",
+			lint:     all,
+			input:    "a: .inf\nb: -.Inf\nc: +.INF\n",
+			expected: []string{`1:4 forbidden infinite value ".inf"`, `2:4 forbidden infinite value "-.Inf"`, `3:4 forbidden infinite value "+.INF"`},
+		},
+		{
+			name:  "Quoted",
+			lint:  all,
+			input: "a: \".5\"\nb: '1e3'\nc: \".nan\"\nd: '-.inf'\n",
+		},
+		{
+			name:  "Tagged",
+			lint:  all,
+			input: "a: !!str .5\nb: !!float 1e3\n",
+		},
+		{
+			name:  "Block Scalar",
+			lint:  all,
+			input: "a: |-\n  .5\n",
+		},
+		{
+			name:  "Disabled",
+			lint:  FloatValues{},
+			input: "a: .5\nb: 1e3\nc: .nan\nd: .inf\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, problemPositions(tt.input, tt.lint))
+		})
+	}
+}
+
+func TestFloatValuesFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name:     "Numeral Before Decimal",
+			lint:     FloatValues{RequireNumeralBeforeDecimal: true},
+			input:    "a: .5\nb: -.25\nc: [+.5, 1]\n",
+			expected: "a: 0.5\nb: -0.25\nc: [+0.5, 1]\n",
+		},
+	})
+}
@@ -1,18 +1,21 @@
 package lint
 
 import (
-	"errors"
 	"iter"
 
 	"github.com/goccy/go-yaml/token"
 )
 
-var ErrHypensMaxSpacesAfter = errors.New("too many spaces after hypen")
+var ErrHypensMaxSpacesAfter = newError("too many spaces after hypen")
 
 type Hyphens struct {
 	MaxSpacesAfter int
 }
 
+func (h Hyphens) Name() string {
+	return "hyphens"
+}
+
 func (h Hyphens) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {
 		if h.MaxSpacesAfter > 0 {
@@ -30,14 +33,11 @@ func (h Hyphens) checkMaxSpacesAfter(ctx tokenContext, yield func(Problem) bool)
 		return
 	}
 
-	leadingSpaces := leadingSpaces(ctx.nextToken.Origin)
+	spaces := spacesBetween(ctx.currentToken, ctx.nextToken)
 
-	if leadingSpaces > h.MaxSpacesAfter {
-		problem := problem(
-			ctx.nextToken.Position.Line,
-			ctx.nextToken.Position.Column+leadingSpaces,
-			ErrHypensMaxSpacesAfter,
-		)
+	if spaces > h.MaxSpacesAfter {
+		problem := spacesProblem(ctx.currentToken, spaces, ErrHypensMaxSpacesAfter).
+			withFix(respace(ctx.currentToken, spaces, h.MaxSpacesAfter))
 		if !yield(problem) {
 			return
 		}
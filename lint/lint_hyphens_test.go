@@ -0,0 +1,84 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyphens(t *testing.T) {
+	tests := []struct {
+		name        string
+		lint        Linter
+		input       string
+		expectedErr error
+	}{
+		{
+			name: "MaxSpacesAfter Pass",
+			lint: Hyphens{
+				MaxSpacesAfter: 1,
+			},
+			input: `
+---
+- item 1
+- item 2
+`,
+			expectedErr: nil,
+		},
+		{
+			name: "MaxSpacesAfter Trailing Spaces",
+			lint: Hyphens{
+				MaxSpacesAfter: 1,
+			},
+			input:       "- a  \n- b\t\n",
+			expectedErr: nil,
+		},
+		{
+			name: "MaxSpacesAfter Fail",
+			lint: Hyphens{
+				MaxSpacesAfter: 1,
+			},
+			input: `
+---
+- item 1
+-   item 2
+`,
+			expectedErr: ErrHypensMaxSpacesAfter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			problem := Lint([]byte(tt.input), tt.lint)
+			if problem == nil {
+				assert.Nil(t, tt.expectedErr, "expected problem but got nil")
+				return
+			}
+
+			assert.ErrorIs(t, problem.Error, tt.expectedErr)
+		})
+	}
+}
+
+func TestHyphensFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name: "MaxSpacesAfter",
+			lint: Hyphens{
+				MaxSpacesAfter: 1,
+			},
+			input:    "---\n-   item 1\n- item 2\n-    - nested\n",
+			expected: "---\n- item 1\n- item 2\n- - nested\n",
+		},
+		{
+			name: "MaxSpacesAfter Trailing Spaces",
+			lint: Hyphens{
+				MaxSpacesAfter: 1,
+			},
+			input:    "-  a  \n-   b\t\n",
+			expected: "- a  \n- b\t\n",
+		},
+	})
+}
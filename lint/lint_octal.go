@@ -1,15 +1,14 @@
 package lint
 
 import (
-	"errors"
 	"iter"
 
 	"github.com/goccy/go-yaml/token"
 )
 
 var (
-	ErrOctalImplicit = errors.New("implicit octal literals are forbidden")
-	ErrExplicitOctal = errors.New("explicit octal literals are forbidden")
+	ErrOctalImplicit = newError("implicit octal literals are forbidden")
+	ErrExplicitOctal = newError("explicit octal literals are forbidden")
 )
 
 type Octal struct {
@@ -17,6 +16,10 @@ type Octal struct {
 	ForbidExplicitOctal bool
 }
 
+func (o Octal) Name() string {
+	return "octal"
+}
+
 func (o Octal) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {
 		if o.ForbidImplicitOctal {
@@ -43,11 +46,7 @@ func (o Octal) checkImplicitOctal(ctx tokenContext, yield func(Problem) bool) {
 	}
 
 	if ctx.currentToken.Value[0] == '0' && ctx.currentToken.Value[1] != 'o' {
-		problem := problem(
-			ctx.currentToken.Position.Line,
-			ctx.currentToken.Position.Column,
-			ErrOctalImplicit,
-		)
+		problem := tokenProblem(ctx.currentToken, ErrOctalImplicit)
 		if !yield(problem) {
 			return
 		}
@@ -64,11 +63,7 @@ func (o Octal) checkExplicitOctal(ctx tokenContext, yield func(Problem) bool) er
 	}
 
 	if ctx.currentToken.Value[0] == '0' && ctx.currentToken.Value[1] == 'o' {
-		problem := problem(
-			ctx.currentToken.Position.Line,
-			ctx.currentToken.Position.Column,
-			ErrExplicitOctal,
-		)
+		problem := tokenProblem(ctx.currentToken, ErrExplicitOctal)
 		if !yield(problem) {
 			return nil
 		}
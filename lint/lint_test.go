@@ -0,0 +1,717 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-yaml/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		lint     Linter
+		input    string
+		expected Problem
+		text     string
+	}{
+		{
+			name:  "TrailingSpaces",
+			lint:  TrailingSpaces{},
+			input: "key: value   \n",
+			expected: Problem{
+				Line: 1, Column: 11, EndLine: 1, EndColumn: 14,
+				Offset: 10, EndOffset: 13,
+				Rule: "trailing-spaces",
+			},
+			text: "   ",
+		},
+		{
+			name:  "TrailingSpaces Multibyte",
+			lint:  TrailingSpaces{},
+			input: "a: b\nkey: éèê  \n",
+			expected: Problem{
+				Line: 2, Column: 9, EndLine: 2, EndColumn: 11,
+				Offset: 16, EndOffset: 18,
+				Rule: "trailing-spaces",
+			},
+			text: "  ",
+		},
+		{
+			name:  "Braces Forbidden",
+			lint:  Braces{Forbid: ForbidBracesAll},
+			input: "é: { a: 1, b: { c: 2 } }\n",
+			expected: Problem{
+				Line: 1, Column: 4, EndLine: 1, EndColumn: 25,
+				Offset: 4, EndOffset: 25,
+				Rule: "braces",
+			},
+			text: "{ a: 1, b: { c: 2 } }",
+		},
+		{
+			name:  "Brackets TooManySpaces",
+			lint:  Brackets{MaxSpacesInside: 1},
+			input: "key: [ a, b   ]\n",
+			expected: Problem{
+				Line: 1, Column: 12, EndLine: 1, EndColumn: 15,
+				Offset: 11, EndOffset: 14,
+				Rule: "brackets",
+			},
+			text: "   ",
+		},
+		{
+			name:  "Comments",
+			lint:  Comments{RequireStartingSpace: true},
+			input: "ключ: значение #комментарий\n",
+			expected: Problem{
+				Line: 1, Column: 16, EndLine: 1, EndColumn: 28,
+				Offset: 27, EndOffset: 50,
+				Rule: "comments",
+			},
+			text: "#комментарий",
+		},
+		{
+			name:  "FloatValues Trailing Whitespace",
+			lint:  FloatValues{RequireNumeralBeforeDecimal: true},
+			input: "k: .5  \n",
+			expected: Problem{
+				Line: 1, Column: 4, EndLine: 1, EndColumn: 6,
+				Offset: 3, EndOffset: 5,
+				Rule: "float-values",
+			},
+			text: ".5",
+		},
+		{
+			name:  "Colons Trailing Whitespace",
+			lint:  Colons{MaxSpacesAfter: 1},
+			input: "key:  value \t\n",
+			expected: Problem{
+				Line: 1, Column: 5, EndLine: 1, EndColumn: 7,
+				Offset: 4, EndOffset: 6,
+				Rule: "colons",
+			},
+			text: "  ",
+		},
+		{
+			name:  "Hyphens Trailing Whitespace",
+			lint:  Hyphens{MaxSpacesAfter: 1},
+			input: "- a\n-   é  \n",
+			expected: Problem{
+				Line: 2, Column: 2, EndLine: 2, EndColumn: 5,
+				Offset: 5, EndOffset: 8,
+				Rule: "hyphens",
+			},
+			text: "   ",
+		},
+		{
+			name:  "Anchors",
+			lint:  Anchors(AnchorOpts{ForbidUndeclaredAliases: true}),
+			input: "- a\n- *missing\n",
+			expected: Problem{
+				Line: 2, Column: 3, EndLine: 2, EndColumn: 11,
+				Offset: 6, EndOffset: 14,
+				Rule: "anchors",
+			},
+			text: "*missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := Lint([]byte(tt.input), tt.lint)
+			if !assert.NotNil(t, problem) {
+				return
+			}
+
+			problem.Error = nil
+			problem.Fixes = nil
+			assert.Equal(t, tt.expected, *problem)
+			assert.Equal(t, tt.text, tt.input[problem.Offset:problem.EndOffset])
+		})
+	}
+}
+
+// problemPositions lints the input and describes each problem by position
+// and message.
+func problemPositions(input string, linters ...Linter) []string {
+	var problems []string
+	for problem := range LintAll([]byte(input), linters...) {
+		problems = append(problems, fmt.Sprintf("%d:%d %s", problem.Line, problem.Column, problem.Message()))
+	}
+	return problems
+}
+
+type fixTest struct {
+	name     string
+	lint     Linter
+	input    string
+	expected string
+}
+
+// runFixTests checks that every input is fixed into the expected output, that
+// the output lints clean and that fixing it again changes nothing.
+func runFixTests(t *testing.T, tests []fixTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, problems := Fix([]byte(tt.input), tt.lint)
+			assert.Equal(t, tt.expected, string(fixed))
+			assert.Empty(t, problems)
+
+			refixed, _ := Fix(fixed, tt.lint)
+			assert.Equal(t, string(fixed), string(refixed))
+		})
+	}
+}
+
+// truncatingLinter reports every mapping value with a fix that drops its last
+// character, which changes the value.
+type truncatingLinter struct{}
+
+func (l truncatingLinter) Name() string {
+	return "truncating"
+}
+
+func (l truncatingLinter) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		if ctx.lastToken == nil || ctx.lastToken.Type != token.MappingValueType {
+			return
+		}
+
+		line, column := tokenEnd(ctx.currentToken)
+		yield(tokenProblem(ctx.currentToken, errors.New("truncating")).
+			withFix(replace(line, column-1, line, column, "")))
+	}
+}
+
+func (l truncatingLinter) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func TestFix(t *testing.T) {
+	t.Run("Conflicting", func(t *testing.T) {
+		lint := Brackets{
+			MinSpacesInsideEmpty: 2,
+			MaxSpacesInsideEmpty: 1,
+		}
+
+		// The fixes undo each other, so Fix has to give up eventually.
+		_, problems := Fix([]byte("key: [ ]\n"), lint)
+		assert.NotEmpty(t, problems)
+	})
+
+	t.Run("Unfixable", func(t *testing.T) {
+		lint := Octal{ForbidImplicitOctal: true}
+
+		fixed, problems := Fix([]byte("key: 010\n"), lint)
+		assert.Equal(t, "key: 010\n", string(fixed))
+		assert.Len(t, problems, 1)
+	})
+
+	t.Run("Multiple Linters", func(t *testing.T) {
+		input := "key: [a,   b ]   \n#comment\nimage:  nginx  \nlist:\n-  a  \n"
+		expected := "key: [ a,   b ]\n# comment\nimage: nginx\nlist:\n- a\n"
+
+		fixed, problems := Fix(
+			[]byte(input),
+			TrailingSpaces{},
+			Comments{RequireStartingSpace: true},
+			Brackets{MinSpacesInside: 1, MaxSpacesInside: 1},
+			Colons{MaxSpacesAfter: 1},
+			Hyphens{MaxSpacesAfter: 1},
+		)
+		assert.Equal(t, expected, string(fixed))
+		assert.Empty(t, problems)
+	})
+
+	t.Run("Value Changing", func(t *testing.T) {
+		input := "key: value\n"
+
+		fixed, problems := Fix([]byte(input), truncatingLinter{})
+		assert.Equal(t, input, string(fixed))
+		assert.Len(t, problems, 1)
+	})
+
+	t.Run("Stateful Linter", func(t *testing.T) {
+		lint := Anchors(AnchorOpts{ForbidDuplicatedAnchors: true})
+
+		_, problems := Fix([]byte("- &a 1   \n- *a\n"), lint, TrailingSpaces{})
+		assert.Empty(t, problems)
+	})
+}
+
+func TestLintAllSourceOrder(t *testing.T) {
+	const input = `key: [a]   
+#comment
+list: { a: 010 }  
+`
+
+	linters := []Linter{
+		TrailingSpaces{},
+		Comments{RequireStartingSpace: true},
+		Brackets{MinSpacesInside: 1, MaxSpacesInside: 1},
+		Octal{ForbidImplicitOctal: true},
+		Braces{},
+	}
+
+	var positions []string
+	for problem := range LintAll([]byte(input), linters...) {
+		positions = append(positions, fmt.Sprintf("%d:%d", problem.Line, problem.Column))
+	}
+
+	assert.Equal(t, []string{
+		"1:6", "1:8", "1:9",
+		"2:1",
+		"3:8", "3:12", "3:15", "3:17",
+	}, positions)
+}
+
+// lateLinter reports every token at the start of the line of the token
+// before it, the earliest position that Linter allows.
+type lateLinter struct{}
+
+func (l lateLinter) Name() string {
+	return "late"
+}
+
+func (l lateLinter) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		tk := ctx.currentToken
+		if ctx.lastToken != nil {
+			tk = ctx.lastToken
+		}
+		yield(problem(tk.Position.Line, 1, errors.New("late")))
+	}
+}
+
+func (l lateLinter) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func TestLintAllLateProblems(t *testing.T) {
+	const input = "a: 1\nb: 2   \n# x\n\n\n# y\n# z\nc: |\n  x\n\n\nd: 3\n"
+
+	problems := slices.Collect(LintAll([]byte(input), lateLinter{}, TrailingSpaces{}, EmptyLines{}))
+	assert.NotEmpty(t, problems)
+	assert.True(t, slices.IsSortedFunc(problems, compareProblems))
+}
+
+func TestLintAllDeterministic(t *testing.T) {
+	const input = `- &unused1 a
+- &unused2 b
+- &used c
+- *used
+- [ 1, 2 ]   
+`
+
+	linters := []Linter{
+		TrailingSpaces{},
+		Brackets{MaxSpacesInside: 0},
+		Brackets{MaxSpacesInside: 0},
+		Anchors(AnchorOpts{ForbidUnusedAnchors: true}),
+	}
+
+	var problems []string
+	for problem := range LintAll([]byte(input), linters...) {
+		problems = append(problems, fmt.Sprintf("%d:%d %s", problem.Line, problem.Column, problem.Rule))
+	}
+
+	expected := []string{
+		"1:3 anchors",
+		"2:3 anchors",
+		"5:4 brackets",
+		"5:9 brackets",
+		"5:11 trailing-spaces",
+	}
+	assert.Equal(t, expected, problems)
+
+	slices.Reverse(linters)
+	problems = nil
+	for problem := range LintAll([]byte(input), linters...) {
+		problems = append(problems, fmt.Sprintf("%d:%d %s", problem.Line, problem.Column, problem.Rule))
+	}
+	assert.Equal(t, expected, problems)
+}
+
+func TestLintAllConcurrent(t *testing.T) {
+	src := kubernetesCorpus(20)
+	chain := Chain(benchmarkLinters())
+	expected := slices.Collect(LintAll(src, chain...))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, expected, slices.Collect(LintAll(src, chain...)))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		line     int
+		column   int
+		problems int
+	}{
+		{
+			name:     "Unclosed Flow Sequence",
+			input:    "a: [1, 2\nb: 3\n",
+			line:     2,
+			column:   1,
+			problems: 1,
+		},
+		{
+			name:     "Bad Indentation",
+			input:    "a:\n  b: 1\n c: 2   \n",
+			line:     3,
+			column:   2,
+			problems: 2,
+		},
+		{
+			name:     "Tab Indentation",
+			input:    "a:\n\tb: 1\n",
+			line:     2,
+			column:   1,
+			problems: 1,
+		},
+		{
+			name:     "Second Document",
+			input:    "a: 1\n---\nb: {\n",
+			line:     3,
+			column:   4,
+			problems: 1,
+		},
+	}
+
+	linters := []Linter{
+		TrailingSpaces{},
+		Braces{Forbid: ForbidBracesAll},
+		Brackets{Forbid: ForbidBracketsAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := slices.Collect(LintAll([]byte(tt.input), linters...))
+			if !assert.Len(t, problems, tt.problems) {
+				return
+			}
+
+			syntax := problems[0]
+			assert.Equal(t, "syntax", syntax.Rule)
+			assert.ErrorIs(t, syntax.Error, ErrSyntax)
+			assert.Equal(t, tt.line, syntax.Line)
+			assert.Equal(t, tt.column, syntax.Column)
+		})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		problem := Lint([]byte("a: 1\na: 2\n---\nb: [1]\n"), linters[0])
+		assert.Nil(t, problem)
+	})
+}
+
+// cancellingLinter cancels the run once it has seen a number of tokens.
+type cancellingLinter struct {
+	cancel context.CancelFunc
+	after  int
+	seen   *int
+}
+
+func (c cancellingLinter) Name() string {
+	return "cancelling"
+}
+
+func (c cancellingLinter) CheckToken(ctx tokenContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {
+		*c.seen++
+		if *c.seen == c.after {
+			c.cancel()
+		}
+	}
+}
+
+func (c cancellingLinter) CheckLine(ctx lineContext) iter.Seq[Problem] {
+	return func(yield func(Problem) bool) {}
+}
+
+func TestLintContext(t *testing.T) {
+	src := kubernetesCorpus(10)
+
+	t.Run("Complete", func(t *testing.T) {
+		problems, err := LintContext(context.Background(), src, benchmarkLinters()...)
+		assert.NoError(t, err)
+		assert.Equal(t, slices.Collect(LintAll(src, benchmarkLinters()...)), problems)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		problems, err := LintContext(ctx, src, TrailingSpaces{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("Cancelled While Linting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var seen int
+		lint := cancellingLinter{cancel: cancel, after: 10, seen: &seen}
+
+		_, err := LintContext(ctx, src, lint, TrailingSpaces{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 10, seen)
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+
+		fixed, _, err := FixContext(ctx, src, TrailingSpaces{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, src, fixed)
+	})
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []sourceLine
+		bom      bool
+	}{
+		{
+			name:     "Empty",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:  "Terminators",
+			input: "a\nb\r\nc",
+			expected: []sourceLine{
+				{text: "a", terminator: "\n"},
+				{text: "b", terminator: "\r\n"},
+				{text: "c", terminator: ""},
+			},
+		},
+		{
+			name:  "Blank Lines",
+			input: "\n\r\n",
+			expected: []sourceLine{
+				{text: "", terminator: "\n"},
+				{text: "", terminator: "\r\n"},
+			},
+		},
+		{
+			name:  "BOM",
+			input: "\ufeffa: 1\n",
+			expected: []sourceLine{
+				{text: "a: 1", terminator: "\n"},
+			},
+			bom: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, bom := splitLines([]byte(tt.input))
+			assert.Equal(t, tt.expected, lines)
+			assert.Equal(t, tt.bom, bom)
+		})
+	}
+}
+
+func TestLintAllLines(t *testing.T) {
+	t.Run("Long Line", func(t *testing.T) {
+		input := "cert: " + strings.Repeat("A", 256*1024) + "\nkey: value \n"
+
+		problems := slices.Collect(LintAll([]byte(input), TrailingSpaces{}))
+		if assert.Len(t, problems, 1) {
+			assert.Equal(t, 2, problems[0].Line)
+		}
+	})
+
+	t.Run("CRLF", func(t *testing.T) {
+		input := "key: value  \r\nlist: [ a ]\r\n"
+
+		fixed, problems := Fix([]byte(input), TrailingSpaces{}, Brackets{})
+		assert.Equal(t, "key: value\r\nlist: [a]\r\n", string(fixed))
+		assert.Empty(t, problems)
+	})
+
+	t.Run("BOM", func(t *testing.T) {
+		input := "\ufeffkey: [ a ] \n"
+
+		problems := slices.Collect(LintAll([]byte(input), TrailingSpaces{}, Brackets{}))
+		if assert.Len(t, problems, 3) {
+			assert.Equal(t, 7, problems[0].Column)
+			assert.Equal(t, 9, problems[0].Offset)
+			assert.Equal(t, 11, problems[2].Column)
+			assert.Equal(t, " ", input[problems[2].Offset:problems[2].EndOffset])
+		}
+
+		fixed, _ := Fix([]byte(input), TrailingSpaces{}, Brackets{})
+		assert.Equal(t, "\ufeffkey: [a]\n", string(fixed))
+	})
+}
+
+func TestLintAllStop(t *testing.T) {
+	input := strings.Repeat("key: value \n", 100)
+
+	var count int
+	for range LintAll([]byte(input), TrailingSpaces{}) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	assert.Equal(t, 3, count)
+}
+
+// kubernetesCorpus generates a multi-document stream of Kubernetes manifests
+// with a sprinkling of problems for every built-in linter.
+func kubernetesCorpus(documents int) []byte {
+	const manifest = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app-%[1]d
+  labels: {app: app-%[1]d, tier: backend }
+  annotations:
+    checksum/config: &checksum-%[1]d abc123
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: app-%[1]d
+  template:
+    metadata:
+      labels:
+        app: app-%[1]d   
+    spec:
+      containers:
+        -  name: app
+           image: registry.example.com/app:1.%[1]d
+           args: [ "--port", "8080", "--mode", 0755]
+           ports:
+             - containerPort: 8080
+           env:
+             - name: CHECKSUM
+               value: *checksum-%[1]d
+           #resources are set by the platform team
+           resources: {}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-%[1]d
+spec:
+  selector: { app: app-%[1]d }
+  ports:
+    - port: 80
+      targetPort: 8080
+`
+
+	var sb strings.Builder
+	for i := range documents {
+		fmt.Fprintf(&sb, manifest, i)
+	}
+
+	return []byte(sb.String())
+}
+
+func benchmarkLinters() []Linter {
+	return []Linter{
+		Anchors(AnchorOpts{
+			ForbidUndeclaredAliases: true,
+			ForbidDuplicatedAnchors: true,
+			ForbidUnusedAnchors:     true,
+		}),
+		Braces{MaxSpacesInside: 1},
+		Brackets{MaxSpacesInside: 1},
+		Colons{MaxSpacesBefore: 0, MaxSpacesAfter: 1},
+		Commas{MaxSpacesBefore: 0, MinSpacesAfter: 1, MaxSpacesAfter: 1},
+		Comments{RequireStartingSpace: true},
+		EmptyLines{Max: 2},
+		EmptyValues{ForbidInBlockMappings: true, ForbidInFlowMappings: true, ForbidInBlockSequences: true},
+		FloatValues{RequireNumeralBeforeDecimal: true, ForbidScientificNotation: true, ForbidNaN: true, ForbidInf: true},
+		Hyphens{MaxSpacesAfter: 1},
+		Octal{ForbidImplicitOctal: true, ForbidExplicitOctal: true},
+		TrailingSpaces{},
+	}
+}
+
+func BenchmarkLintAll(b *testing.B) {
+	src := kubernetesCorpus(500)
+	linters := benchmarkLinters()
+
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+
+	for range b.N {
+		for range LintAll(src, linters...) {
+		}
+	}
+}
+
+func BenchmarkLint(b *testing.B) {
+	src := kubernetesCorpus(500)
+	linters := benchmarkLinters()
+
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+
+	for range b.N {
+		Lint(src, linters...)
+	}
+}
+
+func TestProblemMessage(t *testing.T) {
+	problem := Lint([]byte("a: 1 \n"), TrailingSpaces{})
+	assert.Equal(t, "lint error: trailing spaces are forbidden", problem.Error.Error())
+	assert.Equal(t, "trailing spaces are forbidden", problem.Message())
+
+	problem = Lint([]byte("a: [\n"))
+	assert.True(t, strings.HasPrefix(problem.Message(), "syntax error: "))
+}
+
+func TestErrorFromMessage(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected error
+	}{
+		{message: "trailing spaces are forbidden", expected: ErrTrailingSpaces},
+		{message: "too many spaces inside empty braces", expected: ErrBracesTooManySpacesEmpty},
+		{message: "too many blank lines (3 > 2)", expected: ErrEmptyLinesTooMany},
+		{message: `forbidden infinite value ".inf"`, expected: ErrFloatInfinity},
+		{message: "syntax error: mapping values are not allowed", expected: ErrSyntax},
+		{message: "trailing spaces are forbiddenish"},
+		{message: "something else"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			problem := Problem{Error: ErrorFromMessage(tt.message)}
+			assert.Equal(t, "lint error: "+tt.message, problem.Error.Error())
+			assert.Equal(t, tt.message, problem.Message())
+
+			for _, err := range ruleErrors {
+				assert.Equal(t, err == tt.expected, errors.Is(problem.Error, err), err.Error())
+			}
+		})
+	}
+}
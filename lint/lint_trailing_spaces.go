@@ -1,14 +1,18 @@
 package lint
 
 import (
-	"errors"
 	"iter"
+	"unicode/utf8"
 )
 
-var ErrTrailingSpaces = errors.New("trailing spaces are forbidden")
+var ErrTrailingSpaces = newError("trailing spaces are forbidden")
 
 type TrailingSpaces struct{}
 
+func (t TrailingSpaces) Name() string {
+	return "trailing-spaces"
+}
+
 func (t TrailingSpaces) CheckToken(ctx tokenContext) iter.Seq[Problem] {
 	return func(yield func(Problem) bool) {}
 }
@@ -18,11 +22,28 @@ func (t TrailingSpaces) CheckLine(ctx lineContext) iter.Seq[Problem] {
 		trailingSpaces := trailingSpaces(ctx.currentLine)
 
 		if trailingSpaces > 0 {
-			problem := problem(
+			lineLength := utf8.RuneCountInString(ctx.currentLine)
+
+			problem := problemRange(
+				ctx.currentLineNumber,
+				lineLength-trailingSpaces+1,
 				ctx.currentLineNumber,
-				trailingSpaces+1,
+				lineLength+1,
 				ErrTrailingSpaces,
 			)
+
+			// Trailing spaces in a block scalar are part of its value, so
+			// they are reported but left for the author to remove.
+			if !ctx.inBlockScalar(ctx.currentLineNumber) {
+				problem = problem.withFix(replace(
+					problem.Line,
+					problem.Column,
+					problem.EndLine,
+					problem.EndColumn,
+					"",
+				))
+			}
+
 			if !yield(problem) {
 				return
 			}
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrailingSpaces(t *testing.T) {
+	const pass1 = `
+---
+key: value
+list:
+  - item
+`
+
+	const fail1 = "---\nkey: value \n"
+
+	t.Run("Pass", func(t *testing.T) {
+		problem := Lint([]byte(pass1), TrailingSpaces{})
+		assert.Nil(t, problem)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		problem := Lint([]byte(fail1), TrailingSpaces{})
+		if assert.NotNil(t, problem) {
+			assert.ErrorIs(t, problem.Error, ErrTrailingSpaces)
+		}
+	})
+}
+
+func TestTrailingSpacesFix(t *testing.T) {
+	runFixTests(t, []fixTest{
+		{
+			name:     "Single Line",
+			lint:     TrailingSpaces{},
+			input:    "---\nkey: value   \n",
+			expected: "---\nkey: value\n",
+		},
+		{
+			name:     "Multiple Lines",
+			lint:     TrailingSpaces{},
+			input:    "--- \nkey: é  \nlist:\n  - item \n",
+			expected: "---\nkey: é\nlist:\n  - item\n",
+		},
+		{
+			name:     "Blank Line",
+			lint:     TrailingSpaces{},
+			input:    "a: 1\n    \nb: 2\n",
+			expected: "a: 1\n\nb: 2\n",
+		},
+	})
+}
+
+func TestTrailingSpacesBlockScalar(t *testing.T) {
+	const input = "a: |\n  x  \n  y\nb: >\n  z \n"
+
+	fixed, problems := Fix([]byte(input), TrailingSpaces{})
+	assert.Equal(t, input, string(fixed))
+	if assert.Len(t, problems, 2) {
+		assert.ErrorIs(t, problems[0].Error, ErrTrailingSpaces)
+		assert.Empty(t, problems[0].Fixes)
+		assert.Empty(t, problems[1].Fixes)
+	}
+}
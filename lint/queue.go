@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"cmp"
+	"container/heap"
+)
+
+// problemQueue holds problems until the walk over the source has moved past
+// them, and hands them out ordered by position and rule. Exact duplicates
+// are dropped.
+type problemQueue struct {
+	problems []queuedProblem
+	seq      int
+	last     *Problem
+}
+
+type queuedProblem struct {
+	Problem
+	// seq breaks ties between problems at the same position, keeping the
+	// order in which they were reported.
+	seq int
+}
+
+func (q *problemQueue) Len() int { return len(q.problems) }
+
+func (q *problemQueue) Less(i, j int) bool {
+	a, b := q.problems[i], q.problems[j]
+
+	if c := compareProblems(a.Problem, b.Problem); c != 0 {
+		return c < 0
+	}
+	return a.seq < b.seq
+}
+
+// compareProblems orders problems by line, column and rule. The remaining
+// fields only break ties, so that duplicates end up next to each other.
+func compareProblems(a, b Problem) int {
+	return cmp.Or(
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Column, b.Column),
+		cmp.Compare(a.Rule, b.Rule),
+		cmp.Compare(a.EndLine, b.EndLine),
+		cmp.Compare(a.EndColumn, b.EndColumn),
+		cmp.Compare(errorString(a.Error), errorString(b.Error)),
+	)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func (q *problemQueue) Swap(i, j int) {
+	q.problems[i], q.problems[j] = q.problems[j], q.problems[i]
+}
+
+func (q *problemQueue) Push(x any) {
+	q.problems = append(q.problems, x.(queuedProblem))
+}
+
+func (q *problemQueue) Pop() any {
+	last := q.problems[len(q.problems)-1]
+	q.problems = q.problems[:len(q.problems)-1]
+	return last
+}
+
+func (q *problemQueue) push(p Problem) {
+	heap.Push(q, queuedProblem{Problem: p, seq: q.seq})
+	q.seq++
+}
+
+// flush yields every queued problem that starts before the given line. It
+// returns false if yield asked to stop.
+func (q *problemQueue) flush(line int, yield func(Problem) bool) bool {
+	for q.Len() > 0 && q.problems[0].Line < line {
+		p := heap.Pop(q).(queuedProblem)
+		if q.last != nil && compareProblems(*q.last, p.Problem) == 0 {
+			continue
+		}
+
+		q.last = &p.Problem
+		if !yield(p.Problem) {
+			return false
+		}
+	}
+
+	return true
+}
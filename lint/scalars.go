@@ -0,0 +1,52 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/goccy/go-yaml/token"
+)
+
+// blockScalarLines holds the lines that are the content of a literal or
+// folded block scalar. Blank lines among them are part of the value rather
+// than layout.
+type blockScalarLines map[int]struct{}
+
+func findBlockScalarLines(tokens token.Tokens) blockScalarLines {
+	lines := make(blockScalarLines)
+
+	for i, tk := range tokens {
+		if tk.Type != token.LiteralType && tk.Type != token.FoldedType {
+			continue
+		}
+
+		// A comment can follow the header on its line, before the body.
+		next := i + 1
+		for next < len(tokens) && tokens[next].Type == token.CommentType {
+			next++
+		}
+		if next == len(tokens) || tokens[next].Type != token.StringType {
+			continue
+		}
+
+		// The scalar's origin runs from the line after the header up to the
+		// next token, including any trailing blank lines. Those only belong
+		// to the value with keep chomping.
+		body := strings.Split(strings.TrimSuffix(tokens[next].Origin, "\n"), "\n")
+		if !strings.Contains(tk.Value, "+") {
+			for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+				body = body[:len(body)-1]
+			}
+		}
+
+		for n := range body {
+			lines[tk.Position.Line+1+n] = struct{}{}
+		}
+	}
+
+	return lines
+}
+
+func (b blockScalarLines) contains(line int) bool {
+	_, ok := b[line]
+	return ok
+}
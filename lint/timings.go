@@ -0,0 +1,98 @@
+package lint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timings accumulates the time spent lexing sources and in each linter over
+// any number of runs. It is safe for concurrent use.
+type Timings struct {
+	mu      sync.Mutex
+	lex     time.Duration
+	linters map[string]LinterTimings
+}
+
+// LinterTimings is the cumulative time spent in the methods of a linter.
+type LinterTimings struct {
+	CheckToken time.Duration
+	CheckLine  time.Duration
+	CheckFile  time.Duration
+}
+
+type timingsKey struct{}
+
+// WithTimings returns a context that makes LintContext and FixContext record
+// their timings in t.
+func WithTimings(ctx context.Context, t *Timings) context.Context {
+	return context.WithValue(ctx, timingsKey{}, t)
+}
+
+func timingsFrom(ctx context.Context) *Timings {
+	t, _ := ctx.Value(timingsKey{}).(*Timings)
+	return t
+}
+
+// Lex returns the time spent lexing.
+func (t *Timings) Lex() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.lex
+}
+
+// Linters returns the time spent in each linter, by linter name.
+func (t *Timings) Linters() map[string]LinterTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	linters := make(map[string]LinterTimings, len(t.linters))
+	for name, timings := range t.linters {
+		linters[name] = timings
+	}
+	return linters
+}
+
+// fileTimings collects the timings of a single run without locking, to be
+// merged into the shared Timings at the end.
+type fileTimings struct {
+	lex     time.Duration
+	linters map[string]LinterTimings
+}
+
+func (f *fileTimings) addToken(name string, d time.Duration) {
+	timings := f.linters[name]
+	timings.CheckToken += d
+	f.linters[name] = timings
+}
+
+func (f *fileTimings) addLine(name string, d time.Duration) {
+	timings := f.linters[name]
+	timings.CheckLine += d
+	f.linters[name] = timings
+}
+
+func (f *fileTimings) addFile(name string, d time.Duration) {
+	timings := f.linters[name]
+	timings.CheckFile += d
+	f.linters[name] = timings
+}
+
+func (t *Timings) merge(f *fileTimings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.linters == nil {
+		t.linters = make(map[string]LinterTimings)
+	}
+
+	t.lex += f.lex
+	for name, timings := range f.linters {
+		total := t.linters[name]
+		total.CheckToken += timings.CheckToken
+		total.CheckLine += timings.CheckLine
+		total.CheckFile += timings.CheckFile
+		t.linters[name] = total
+	}
+}
@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimings(t *testing.T) {
+	var timings Timings
+	ctx := WithTimings(context.Background(), &timings)
+
+	src := []byte("list: [ a ] \nmap: { b: 1 }\n")
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := LintContext(ctx, src, TrailingSpaces{}, Brackets{}, Braces{}, EmptyValues{})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Positive(t, timings.Lex())
+
+	linters := timings.Linters()
+	assert.Equal(t, []string{"braces", "brackets", "empty-values", "trailing-spaces"}, slices.Sorted(maps.Keys(linters)))
+	assert.Positive(t, linters["brackets"].CheckToken+linters["brackets"].CheckLine)
+	assert.Positive(t, linters["empty-values"].CheckFile)
+}
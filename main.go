@@ -1,72 +1,93 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io/fs"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+	"time"
 
-	"github.com/bmatcuk/doublestar/v4"
-	"github.com/cedws/yamllintx/lint"
-	"github.com/goccy/go-yaml"
-	"github.com/goccy/go-yaml/ast"
+	"github.com/cedws/yamllintx/internal/lsp"
+	"github.com/cedws/yamllintx/internal/pretty"
+	"github.com/cedws/yamllintx/internal/serve"
+	"github.com/cedws/yamllintx/runner"
 )
 
-var ruleFactories = map[string]func() lint.Linter{
-	"anchors": func() lint.Linter {
-		return lint.Anchors(lint.AnchorOpts{})
-	},
-	"braces": func() lint.Linter {
-		return lint.Braces{}
-	},
-	"brackets": func() lint.Linter {
-		return lint.Brackets{}
-	},
-	"comments": func() lint.Linter {
-		return lint.Comments{}
-	},
-	"hyphens": func() lint.Linter {
-		return lint.Hyphens{}
-	},
-	"octal": func() lint.Linter {
-		return lint.Octal{}
-	},
-	"trailing-spaces": func() lint.Linter {
-		return lint.TrailingSpaces{}
-	},
-}
-
-type config struct {
-	YamlFiles []string            `yaml:"yaml-files"`
-	Ignore    []string            `yaml:"ignore"`
-	Rules     map[string]ast.Node `yaml:"rules"`
-}
+// version is set at build time.
+var version = "dev"
 
-func unmarshalConfig(file string) config {
-	config := config{
-		YamlFiles: []string{
-			"*.yaml",
-			"*.yml",
-			".yamllint",
-		},
+// toolVersion identifies the build for the cache, so that results from a
+// different build of the linter are never reused.
+func toolVersion() string {
+	if version != "dev" {
+		return version
 	}
 
-	bytes, err := os.ReadFile(file)
-	if err != nil {
-		log.Fatal(err)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
 	}
 
-	if err := yaml.Unmarshal(bytes, &config); err != nil {
-		log.Fatal(err)
+	v := version + " " + info.Main.Version
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+			v += " " + setting.Value
+		}
 	}
 
-	return config
+	return v
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lsp":
+			runLSP(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		}
+	}
+
 	configFile := flag.String("config", "", "config file")
+	fix := flag.Bool("fix", false, "fix problems in place where possible and report the rest")
+	showDiff := flag.Bool("diff", false, "print the fixes as a unified diff instead of applying them")
+
+	cacheLocation := flag.String("cache-location", runner.DefaultCacheLocation, "path of the result cache")
+	noCache := flag.Bool("no-cache", false, "do not read or write the result cache")
+
+	generateBaseline := flag.String("generate-baseline", "", "record the current problems in this baseline file")
+	baselineFile := flag.String("baseline", "", "suppress the problems recorded in this baseline file")
+
+	newFromRev := flag.String("new-from-rev", "", "only report problems on lines changed since this git revision")
+	newFromPatch := flag.String("new-from-patch", "", "only report problems on lines changed by this unified diff")
+
+	staged := flag.Bool("staged", false, "lint the files with changes staged in the git index instead of the working tree")
+	rev := flag.String("rev", "", "lint the files in this git revision instead of the working tree")
+
+	watch := flag.Bool("watch", false, "keep running and lint files again as they change")
+	watchInterval := flag.Duration("watch-interval", time.Second, "how often to poll for changes in watch mode")
+
+	format := flag.String("format", "plain", "output format, plain or pretty")
+
+	showStats := flag.Bool("stats", false, "print a summary of the run, with time spent per linter")
+	statsFormat := flag.String("stats-format", "text", "format of the summary, text or json")
+
+	timeout := flag.Duration("timeout", 0, "time budget for linting each file, 0 for no limit")
+
+	var jobs int
+	flag.IntVar(&jobs, "j", runtime.GOMAXPROCS(0), "number of files to lint concurrently")
+	flag.IntVar(&jobs, "jobs", runtime.GOMAXPROCS(0), "number of files to lint concurrently")
 	flag.Parse()
 
 	if configFile == nil || *configFile == "" {
@@ -74,68 +95,385 @@ func main() {
 		os.Exit(1)
 	}
 
-	config := unmarshalConfig(*configFile)
-	var chain lint.Chain
+	config, err := runner.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		fsys  fs.FS = os.DirFS(".")
+		gitFS *runner.GitFS
+	)
+
+	switch {
+	case *staged:
+		gitFS, err = runner.IndexFS(context.Background())
+	case *rev != "":
+		gitFS, err = runner.RevFS(context.Background(), *rev)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	for rule := range config.Rules {
-		if factory, ok := ruleFactories[rule]; ok {
-			chain = append(chain, factory())
+	if gitFS != nil {
+		if *fix {
+			log.Fatal("-fix cannot be combined with -staged or -rev")
 		}
+		fsys = gitFS
 	}
 
-	files, err := lintableFiles(config, ".")
+	files, err := runner.Discover(fsys, config)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for _, file := range files {
-		fmt.Fprintln(os.Stderr, filepath.Clean(file))
+	var changes *runner.Changes
+
+	switch {
+	case *newFromRev != "":
+		changes, err = runner.ChangesFromRev(context.Background(), *newFromRev)
+	case *newFromPatch != "":
+		changes, err = changesFromPatch(*newFromPatch)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if changes != nil {
+		files = changes.Paths(files)
+	}
+
+	opts := []runner.Option{
+		runner.WithJobs(jobs),
+		runner.WithTimeout(*timeout),
+	}
+
+	if gitFS != nil {
+		opts = append(opts, runner.WithFS(gitFS))
+	}
+
+	var cache *runner.Cache
+
+	switch {
+	case *showDiff:
+		opts = append(opts, runner.WithMode(runner.ModeDiff))
+	case *fix:
+		opts = append(opts, runner.WithMode(runner.ModeFix))
+	case !*noCache && !*watch:
+		cache = runner.OpenCache(*cacheLocation, config, toolVersion())
+		opts = append(opts, runner.WithCache(cache))
+	}
+
+	if *watch {
+		if gitFS != nil || *generateBaseline != "" || *baselineFile != "" {
+			log.Fatal("-watch cannot be combined with -staged, -rev or baselines")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		watchFiles(ctx, *configFile, *watchInterval, changes, opts)
+		return
+	}
+
+	if *generateBaseline != "" {
+		baseline := runner.NewBaseline()
+
+		for result := range runner.Run(context.Background(), config, files, opts...) {
+			if result.Err != nil {
+				log.Fatal(result.Err)
+			}
+			baseline.Add(result)
+		}
+
+		closeGitFS(gitFS)
+
+		if err := baseline.Save(*generateBaseline); err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Fprintf(os.Stderr, "recorded %d problems in %s\n", baseline.Len(), *generateBaseline)
+		saveCache(cache)
+		return
+	}
 
-		bytes, err := os.ReadFile(file)
+	// A diff run reports fixes rather than problems, so it has nothing to
+	// filter and says nothing about which recorded problems remain.
+	var baseline *runner.Baseline
+	if *baselineFile != "" && !*showDiff {
+		baseline, err = runner.LoadBaseline(*baselineFile)
 		if err != nil {
 			log.Fatal(err)
 		}
+	}
+
+	ctx := context.Background()
+
+	var stats *runner.Stats
+	if *showStats {
+		if *statsFormat != "text" && *statsFormat != "json" {
+			log.Fatalf("unknown stats format %q", *statsFormat)
+		}
+
+		stats = runner.NewStats(config)
+		ctx = stats.Context(ctx)
+	}
+
+	var printer *pretty.Printer
+	switch *format {
+	case "plain":
+	case "pretty":
+		p := pretty.ForTerminal(os.Stderr)
+		p.Level = config.Level
+		printer = &p
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	var changed, failed bool
+
+	for result := range runner.Run(ctx, config, files, opts...) {
+		if printer == nil {
+			fmt.Fprintln(os.Stderr, filepath.Clean(result.Path))
+		}
+
+		if result.Err != nil {
+			log.Fatal(result.Err)
+		}
+
+		if baseline != nil {
+			result = baseline.Filter(result)
+		}
+
+		if changes != nil {
+			result = changes.Filter(result)
+		}
+
+		if stats != nil {
+			stats.Add(result)
+		}
+
+		for _, problem := range result.Problems {
+			if config.Level(problem.Rule) == runner.LevelError {
+				failed = true
+			}
+		}
+
+		if result.Diff != "" {
+			fmt.Print(result.Diff)
+			changed = true
+		}
+
+		if printer != nil {
+			result.Path = filepath.Clean(result.Path)
+			if err := printer.Print(os.Stderr, result); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
 
-		for err := range lint.LintAll(bytes, chain...) {
+		for _, err := range result.Problems {
 			fmt.Fprintf(os.Stderr, "  %d:%d\t%s\n", err.Line, err.Column, err.Error)
 		}
 	}
+
+	closeGitFS(gitFS)
+
+	// Problems that were fixed since the baseline was recorded are dropped
+	// from it, so that they cannot come back unnoticed.
+	if baseline != nil && baseline.Shrink() {
+		if err := baseline.Save(*baselineFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if stats != nil {
+		writeStats := stats.WriteText
+		if *statsFormat == "json" {
+			writeStats = stats.WriteJSON
+		}
+
+		fmt.Fprintln(os.Stderr)
+		if err := writeStats(os.Stderr); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	saveCache(cache)
+
+	// Problems left after the baseline and the changed lines have been
+	// filtered fail the run, unless their rules only warn.
+	if changed || failed {
+		os.Exit(1)
+	}
 }
 
-func lintableFiles(config config, dir string) ([]string, error) {
-	var files []string
+// closeGitFS stops the git process behind a GitFS once linting is done.
+func closeGitFS(gitFS *runner.GitFS) {
+	if gitFS == nil {
+		return
+	}
+
+	if err := gitFS.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	walkFunc := func(path string, d fs.DirEntry, err error) error {
-		if d.IsDir() {
-			return nil
+// watchFiles lints the files again whenever they change, redrawing the
+// screen with the problems in every file and a summary after each round.
+func watchFiles(ctx context.Context, configFile string, interval time.Duration, changes *runner.Changes, opts []runner.Option) {
+	for round := range runner.Watch(ctx, configFile, interval, opts...) {
+		if round.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s  %v\n", time.Now().Format(time.TimeOnly), round.Err)
+			continue
 		}
 
-		for _, pattern := range config.Ignore {
-			match, err := doublestar.Match(pattern, path)
-			if err != nil {
-				return err
+		// Clear the screen and move the cursor to the top left.
+		fmt.Fprint(os.Stderr, "\x1b[H\x1b[2J")
+
+		var problems, files int
+
+		for _, result := range round.Results {
+			if changes != nil {
+				result = changes.Filter(result)
 			}
-			if match {
-				return nil
+
+			if result.Err == nil && len(result.Problems) == 0 && result.Diff == "" {
+				continue
 			}
+
+			fmt.Fprintln(os.Stderr, filepath.Clean(result.Path))
+
+			if result.Err != nil {
+				fmt.Fprintf(os.Stderr, "  %v\n", result.Err)
+			}
+
+			if result.Diff != "" {
+				fmt.Print(result.Diff)
+			}
+
+			for _, err := range result.Problems {
+				fmt.Fprintf(os.Stderr, "  %d:%d\t%s\n", err.Line, err.Column, err.Error)
+			}
+
+			problems += len(result.Problems)
+			files++
 		}
 
-		for _, pattern := range config.YamlFiles {
-			match, err := doublestar.Match(pattern, d.Name())
-			if err != nil {
-				return err
+		summary := fmt.Sprintf("%d problems in %d of %d files, linted %d", problems, files, len(round.Results), len(round.Linted))
+		if round.ConfigReloaded {
+			summary += ", config reloaded"
+		}
+
+		fmt.Fprintf(os.Stderr, "\n%s  %s\n", time.Now().Format(time.TimeOnly), summary)
+	}
+}
+
+// runLSP serves the Language Server Protocol over stdio.
+func runLSP(args []string) {
+	flags := flag.NewFlagSet("lsp", flag.ExitOnError)
+	configFile := flags.String("config", "", "config file, relative to the workspace root")
+	flags.Parse(args)
+
+	if *configFile == "" {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	opts := lsp.Options{
+		ConfigPath: *configFile,
+		Version:    toolVersion(),
+	}
+
+	if err := lsp.Serve(context.Background(), os.Stdin, os.Stdout, opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runServe serves the HTTP lint service until interrupted.
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configFile := flags.String("config", "", "config file")
+	listen := flags.String("listen", ":8080", "address to listen on")
+	maxBodyBytes := flags.Int64("max-body-bytes", 1<<20, "largest request body accepted")
+	timeout := flags.Duration("timeout", 10*time.Second, "time budget for each request")
+	reloadInterval := flags.Duration("reload-interval", 2*time.Second, "how often to check the config file for changes")
+	flags.Parse(args)
+
+	if *configFile == "" {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	config, err := runner.OpenConfigFile(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		ticker := time.NewTicker(*reloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
 			}
-			if match {
-				files = append(files, path)
+
+			reloaded, err := config.Reload()
+			if err != nil {
+				log.Printf("failed to reload config, keeping the previous one: %v", err)
+			} else if reloaded {
+				log.Printf("reloaded config")
 			}
 		}
+	}()
 
-		return nil
+	server := &http.Server{
+		Addr: *listen,
+		Handler: serve.Handler(config, serve.Options{
+			MaxBodyBytes: *maxBodyBytes,
+			Timeout:      *timeout,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       *timeout,
+		WriteTimeout:      2 * *timeout,
 	}
 
-	if err := filepath.WalkDir(dir, walkFunc); err != nil {
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("listening on %s", *listen)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+func changesFromPatch(path string) (*runner.Changes, error) {
+	f, err := os.Open(path)
+	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
 
-	return files, nil
+	return runner.ChangesFromPatch(f)
+}
+
+func saveCache(cache *runner.Cache) {
+	if cache == nil {
+		return
+	}
+
+	if err := cache.Save(); err != nil {
+		log.Printf("failed to save cache: %v", err)
+	}
 }
@@ -0,0 +1,230 @@
+package runner
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cedws/yamllintx/lint"
+)
+
+// baselineFormat is bumped whenever the layout of the baseline file changes.
+const baselineFormat = 1
+
+// Baseline records known problems so that they can be suppressed, letting
+// the linter be adopted on files that are not clean yet. Problems are
+// fingerprinted by file, rule and the normalized content of the line they are
+// on rather than by line number, so edits elsewhere in a file do not bring
+// them back.
+//
+// A Baseline is not safe for concurrent use.
+type Baseline struct {
+	entries map[baselineKey]int
+	// matched counts the problems suppressed per entry, for the files
+	// filtered so far.
+	matched map[baselineKey]int
+	files   map[string]struct{}
+}
+
+type baselineKey struct {
+	File        string
+	Rule        string
+	Fingerprint string
+}
+
+type baselineFile struct {
+	Format  int             `json:"format"`
+	Entries []baselineEntry `json:"entries"`
+}
+
+type baselineEntry struct {
+	File        string `json:"file"`
+	Rule        string `json:"rule"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count"`
+}
+
+// NewBaseline returns an empty baseline.
+func NewBaseline() *Baseline {
+	return &Baseline{
+		entries: make(map[baselineKey]int),
+		matched: make(map[baselineKey]int),
+		files:   make(map[string]struct{}),
+	}
+}
+
+// LoadBaseline reads the baseline file at path.
+func LoadBaseline(path string) (*Baseline, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file baselineFile
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return nil, err
+	}
+
+	b := NewBaseline()
+	for _, entry := range file.Entries {
+		key := baselineKey{
+			File:        entry.File,
+			Rule:        entry.Rule,
+			Fingerprint: entry.Fingerprint,
+		}
+		b.entries[key] += entry.Count
+	}
+
+	return b, nil
+}
+
+// Add records the problems of the result in the baseline.
+func (b *Baseline) Add(result FileResult) {
+	for _, problem := range result.Problems {
+		b.entries[fingerprint(result, problem)]++
+	}
+}
+
+// Filter returns the result without the problems recorded in the baseline.
+// Each entry suppresses as many problems as were recorded for it. Only files
+// whose problems are complete are considered by Shrink, so results from
+// ModeDiff, results that timed out and results with an error are not.
+func (b *Baseline) Filter(result FileResult) FileResult {
+	if result.Mode != ModeDiff && !result.TimedOut && result.Err == nil {
+		b.files[result.Path] = struct{}{}
+	}
+
+	var problems []lint.Problem
+
+	for _, problem := range result.Problems {
+		key := fingerprint(result, problem)
+
+		if b.matched[key] < b.entries[key] {
+			b.matched[key]++
+			continue
+		}
+
+		problems = append(problems, problem)
+	}
+
+	result.Problems = problems
+	return result
+}
+
+// Shrink lowers the count of every entry for the files filtered so far to
+// the number of problems it actually suppressed, dropping entries for
+// problems that have been fixed. It reports whether the baseline changed.
+func (b *Baseline) Shrink() bool {
+	var changed bool
+
+	for key, count := range b.entries {
+		if _, ok := b.files[key.File]; !ok {
+			continue
+		}
+
+		matched := b.matched[key]
+		if matched >= count {
+			continue
+		}
+
+		if matched == 0 {
+			delete(b.entries, key)
+		} else {
+			b.entries[key] = matched
+		}
+		changed = true
+	}
+
+	return changed
+}
+
+// Len returns the number of problems recorded in the baseline.
+func (b *Baseline) Len() int {
+	var n int
+	for _, count := range b.entries {
+		n += count
+	}
+	return n
+}
+
+// Save writes the baseline to path, sorted so that it diffs cleanly.
+func (b *Baseline) Save(path string) error {
+	file := baselineFile{
+		Format:  baselineFormat,
+		Entries: []baselineEntry{},
+	}
+
+	keys := slices.SortedFunc(maps.Keys(b.entries), func(a, b baselineKey) int {
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Fingerprint, b.Fingerprint),
+		)
+	})
+
+	for _, key := range keys {
+		file.Entries = append(file.Entries, baselineEntry{
+			File:        key.File,
+			Rule:        key.Rule,
+			Fingerprint: key.Fingerprint,
+			Count:       b.entries[key],
+		})
+	}
+
+	bytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(bytes, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func fingerprint(result FileResult, problem lint.Problem) baselineKey {
+	h := sha256.Sum256([]byte(normalizeLine(lineAround(result.Source, problem.Offset))))
+
+	return baselineKey{
+		File:        result.Path,
+		Rule:        problem.Rule,
+		Fingerprint: hex.EncodeToString(h[:8]),
+	}
+}
+
+// normalizeLine collapses all whitespace in the line, so that reindenting it
+// or fixing its spacing keeps the fingerprint stable.
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// lineAround returns the line of the source that contains the byte offset.
+func lineAround(src []byte, offset int) string {
+	offset = min(max(offset, 0), len(src))
+
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	end := len(src)
+	if i := bytes.IndexByte(src[offset:], '\n'); i >= 0 {
+		end = offset + i
+	}
+
+	return string(src[start:end])
+}
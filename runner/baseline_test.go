@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func lintResult(path, src string) FileResult {
+	return FileResult{
+		Path:     path,
+		Source:   []byte(src),
+		Problems: slices.Collect(lint.LintAll([]byte(src), lint.TrailingSpaces{}, lint.Brackets{})),
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	const original = "a: 1 \nlist: [ a ]\nb: 2 \n"
+
+	t.Run("Suppress", func(t *testing.T) {
+		baseline := NewBaseline()
+		baseline.Add(lintResult("a.yaml", original))
+		assert.Equal(t, 4, baseline.Len())
+
+		result := baseline.Filter(lintResult("a.yaml", original))
+		assert.Empty(t, result.Problems)
+	})
+
+	t.Run("Moved Lines", func(t *testing.T) {
+		baseline := NewBaseline()
+		baseline.Add(lintResult("a.yaml", original))
+
+		result := baseline.Filter(lintResult("a.yaml", "# new comment\n\n"+original))
+		assert.Empty(t, result.Problems)
+	})
+
+	t.Run("New Problems", func(t *testing.T) {
+		baseline := NewBaseline()
+		baseline.Add(lintResult("a.yaml", original))
+
+		result := baseline.Filter(lintResult("a.yaml", original+"c: 3 \nb: 2 \n"))
+		if assert.Len(t, result.Problems, 2) {
+			assert.Equal(t, 4, result.Problems[0].Line)
+			assert.Equal(t, 5, result.Problems[1].Line)
+		}
+
+		result = baseline.Filter(lintResult("b.yaml", original))
+		assert.Len(t, result.Problems, 4)
+	})
+
+	t.Run("Shrink", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "baseline.json")
+
+		baseline := NewBaseline()
+		baseline.Add(lintResult("a.yaml", original))
+		baseline.Add(lintResult("b.yaml", original))
+		assert.NoError(t, baseline.Save(path))
+
+		baseline, err := LoadBaseline(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 8, baseline.Len())
+
+		result := baseline.Filter(lintResult("a.yaml", "a: 1\nlist: [ a ]\nb: 2 \n"))
+		assert.Empty(t, result.Problems)
+
+		assert.True(t, baseline.Shrink())
+		assert.Equal(t, 7, baseline.Len())
+		assert.False(t, baseline.Shrink())
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.yaml": {Data: []byte(original)},
+		}
+		config, err := ParseConfig([]byte("rules:\n  trailing-spaces: {}\n  brackets: {}\n"))
+		assert.NoError(t, err)
+
+		baseline := NewBaseline()
+		baseline.Add(lintResult("a.yaml", original))
+
+		for result := range Run(context.Background(), config, []string{"a.yaml"}, WithFS(fsys), WithMode(ModeDiff)) {
+			assert.NotEmpty(t, result.Diff)
+			baseline.Filter(result)
+		}
+
+		assert.False(t, baseline.Shrink())
+		assert.Equal(t, 4, baseline.Len())
+	})
+
+	t.Run("Incomplete Results", func(t *testing.T) {
+		baseline := NewBaseline()
+		baseline.Add(lintResult("a.yaml", original))
+		baseline.Add(lintResult("b.yaml", original))
+
+		timedOut := FileResult{Path: "a.yaml", Source: []byte(original), TimedOut: true}
+		timedOut.Problems = []lint.Problem{lint.TimeoutProblem(timedOut.Source, time.Second)}
+		baseline.Filter(timedOut)
+		baseline.Filter(FileResult{Path: "b.yaml", Err: errors.New("permission denied")})
+
+		assert.False(t, baseline.Shrink())
+		assert.Equal(t, 8, baseline.Len())
+	})
+}
@@ -0,0 +1,218 @@
+package runner
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cedws/yamllintx/lint"
+)
+
+const (
+	// DefaultCacheLocation is where the yamllintx command keeps its cache.
+	DefaultCacheLocation = ".yamllintx-cache"
+
+	// cacheFormat is bumped whenever the layout of the cache file changes,
+	// which discards every existing cache.
+	cacheFormat = 2
+
+	// Entries that no run has used for cacheMaxAge are evicted, and only the
+	// cacheMaxEntries most recently used entries are kept.
+	cacheMaxAge     = 7 * 24 * time.Hour
+	cacheMaxEntries = 100_000
+)
+
+// Cache stores the problems found in files keyed by a hash of their content,
+// the config and the tool version, so that unchanged files are not linted
+// again on the next run. It is safe for concurrent use.
+type Cache struct {
+	path    string
+	salt    []byte
+	now     time.Time
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheFile struct {
+	Format  int                   `json:"format"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+type cacheEntry struct {
+	Problems []cachedProblem `json:"problems"`
+	LastUsed int64           `json:"lastUsed"`
+}
+
+type cachedProblem struct {
+	Line      int         `json:"line"`
+	Column    int         `json:"column"`
+	EndLine   int         `json:"endLine"`
+	EndColumn int         `json:"endColumn"`
+	Offset    int         `json:"offset"`
+	EndOffset int         `json:"endOffset"`
+	Rule      string      `json:"rule"`
+	Message   string      `json:"message"`
+	Fixes     []lint.Edit `json:"fixes,omitempty"`
+}
+
+// OpenCache loads the cache at path. A missing or unreadable cache is treated
+// as empty. Entries are only reused by runs with the same config and version
+// of the linter.
+func OpenCache(path string, cfg Config, version string) *Cache {
+	c := &Cache{
+		path:    path,
+		salt:    append([]byte(version+"\x00"), cfg.Hash()...),
+		now:     time.Now(),
+		entries: make(map[string]cacheEntry),
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(bytes, &file); err != nil || file.Format != cacheFormat {
+		return c
+	}
+
+	if file.Entries != nil {
+		c.entries = file.Entries
+	}
+
+	return c
+}
+
+func (c *Cache) key(src []byte) string {
+	h := sha256.New()
+	h.Write(c.salt)
+	h.Write([]byte{0})
+	h.Write(src)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get returns the problems cached for the source, if any.
+func (c *Cache) get(src []byte) ([]lint.Problem, bool) {
+	key := c.key(src)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry.LastUsed = c.now.Unix()
+	c.entries[key] = entry
+
+	problems := make([]lint.Problem, 0, len(entry.Problems))
+	for _, p := range entry.Problems {
+		problems = append(problems, lint.Problem{
+			Line:      p.Line,
+			Column:    p.Column,
+			EndLine:   p.EndLine,
+			EndColumn: p.EndColumn,
+			Offset:    p.Offset,
+			EndOffset: p.EndOffset,
+			Rule:      p.Rule,
+			Error:     lint.ErrorFromMessage(p.Message),
+			Fixes:     p.Fixes,
+		})
+	}
+
+	return problems, true
+}
+
+func (c *Cache) put(src []byte, problems []lint.Problem) {
+	entry := cacheEntry{
+		Problems: make([]cachedProblem, 0, len(problems)),
+		LastUsed: c.now.Unix(),
+	}
+
+	for _, p := range problems {
+		entry.Problems = append(entry.Problems, cachedProblem{
+			Line:      p.Line,
+			Column:    p.Column,
+			EndLine:   p.EndLine,
+			EndColumn: p.EndColumn,
+			Offset:    p.Offset,
+			EndOffset: p.EndOffset,
+			Rule:      p.Rule,
+			Message:   p.Message(),
+			Fixes:     p.Fixes,
+		})
+	}
+
+	key := c.key(src)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+}
+
+// evict drops entries that have not been used recently.
+func (c *Cache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := c.now.Add(-cacheMaxAge).Unix()
+	maps.DeleteFunc(c.entries, func(_ string, entry cacheEntry) bool {
+		return entry.LastUsed < cutoff
+	})
+
+	if len(c.entries) <= cacheMaxEntries {
+		return
+	}
+
+	keys := slices.SortedFunc(maps.Keys(c.entries), func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(c.entries[b].LastUsed, c.entries[a].LastUsed),
+			cmp.Compare(a, b),
+		)
+	})
+	for _, key := range keys[cacheMaxEntries:] {
+		delete(c.entries, key)
+	}
+}
+
+// Save evicts stale entries and writes the cache. The cache is written to a
+// temporary file that is then renamed over the old one, so concurrent runs
+// never see a partially written cache.
+func (c *Cache) Save() error {
+	c.evict()
+
+	c.mu.Lock()
+	bytes, err := json.Marshal(cacheFile{
+		Format:  cacheFormat,
+		Entries: c.entries,
+	})
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bytes); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig(src string) Config {
+	return Config{hash: []byte(src)}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultCacheLocation)
+	src := []byte("key: value \n")
+
+	problems := slices.Collect(lint.LintAll(src, lint.TrailingSpaces{}))
+
+	t.Run("Round Trip", func(t *testing.T) {
+		c := OpenCache(path, testConfig("config"), "test")
+		_, ok := c.get(src)
+		assert.False(t, ok)
+
+		c.put(src, problems)
+		assert.NoError(t, c.Save())
+
+		c = OpenCache(path, testConfig("config"), "test")
+		cached, ok := c.get(src)
+		assert.True(t, ok)
+		assertSameProblems(t, problems, cached)
+	})
+
+	t.Run("Rule Errors", func(t *testing.T) {
+		src := []byte("a: [ 1 ]\n\n\n\nb: .5\n")
+		fresh := slices.Collect(lint.LintAll(src,
+			lint.Brackets{},
+			lint.EmptyLines{Max: 2},
+			lint.FloatValues{RequireNumeralBeforeDecimal: true},
+		))
+
+		c := OpenCache(path, testConfig("config"), "test")
+		c.put(src, fresh)
+		assert.NoError(t, c.Save())
+
+		c = OpenCache(path, testConfig("config"), "test")
+		cached, ok := c.get(src)
+		assert.True(t, ok)
+		assertSameProblems(t, fresh, cached)
+
+		assert.ErrorIs(t, cached[0].Error, lint.ErrBracketsTooManySpaces)
+		assert.ErrorIs(t, cached[1].Error, lint.ErrBracketsTooManySpaces)
+		assert.ErrorIs(t, cached[2].Error, lint.ErrEmptyLinesTooMany)
+		assert.ErrorIs(t, cached[3].Error, lint.ErrFloatMissingNumeral)
+	})
+
+	t.Run("Config Changed", func(t *testing.T) {
+		c := OpenCache(path, testConfig("other config"), "test")
+		_, ok := c.get(src)
+		assert.False(t, ok)
+	})
+
+	t.Run("Version Changed", func(t *testing.T) {
+		c := OpenCache(path, testConfig("config"), "other")
+		_, ok := c.get(src)
+		assert.False(t, ok)
+	})
+
+	t.Run("Content Changed", func(t *testing.T) {
+		c := OpenCache(path, testConfig("config"), "test")
+		_, ok := c.get([]byte("key: value\n"))
+		assert.False(t, ok)
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		c := OpenCache(path, testConfig("config"), "test")
+		c.now = c.now.Add(cacheMaxAge + time.Hour)
+		c.put([]byte("other: value\n"), nil)
+		assert.NoError(t, c.Save())
+
+		c = OpenCache(path, testConfig("config"), "test")
+		_, ok := c.get(src)
+		assert.False(t, ok)
+		_, ok = c.get([]byte("other: value\n"))
+		assert.True(t, ok)
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+
+		c := OpenCache(path, testConfig("config"), "test")
+		assert.Empty(t, c.entries)
+	})
+}
+
+// assertSameProblems checks that cached problems match the fresh ones they
+// were stored from, comparing errors by their messages.
+func assertSameProblems(t *testing.T, expected, actual []lint.Problem) {
+	t.Helper()
+
+	if !assert.Len(t, actual, len(expected)) {
+		return
+	}
+
+	for i := range expected {
+		want, got := expected[i], actual[i]
+		assert.Equal(t, want.Error.Error(), got.Error.Error())
+		assert.Equal(t, want.Message(), got.Message())
+
+		want.Error, got.Error = nil, nil
+		assert.Equal(t, want, got)
+	}
+}
@@ -0,0 +1,160 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/cedws/yamllintx/internal/diff"
+	"github.com/cedws/yamllintx/lint"
+)
+
+// Changes records which lines of which files a change touched, so that
+// linting can be limited to the problems it introduced.
+type Changes struct {
+	files map[string]fileChanges
+}
+
+type fileChanges struct {
+	// added is set for a file that did not exist before the change.
+	added bool
+	lines map[int]struct{}
+}
+
+// ChangesFromPatch reads the changes from a unified diff. Paths in the diff
+// are taken to be relative to the working directory.
+func ChangesFromPatch(r io.Reader) (*Changes, error) {
+	diffs, err := diff.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Changes{files: make(map[string]fileChanges)}
+
+	for _, d := range diffs {
+		if d.NewName == "" {
+			continue
+		}
+
+		name := path.Clean(d.NewName)
+		file, ok := c.files[name]
+		if !ok {
+			file = fileChanges{lines: make(map[int]struct{})}
+		}
+		file.added = file.added || d.OldName == ""
+
+		for _, line := range d.Added {
+			file.lines[line] = struct{}{}
+		}
+
+		c.files[name] = file
+	}
+
+	return c, nil
+}
+
+// ChangesFromRev returns the changes between the git revision rev and the
+// working tree. Untracked files count as added.
+func ChangesFromRev(ctx context.Context, rev string) (*Changes, error) {
+	patch, err := git(ctx, "-c", "core.quotePath=false", "diff", "--relative", "--no-color", "--no-ext-diff", "--unified=0", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ChangesFromPatch(bytes.NewReader(patch))
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git(ctx, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range strings.Split(string(untracked), "\x00") {
+		if name != "" {
+			c.files[path.Clean(name)] = fileChanges{added: true}
+		}
+	}
+
+	return c, nil
+}
+
+func git(ctx context.Context, args ...string) ([]byte, error) {
+	return gitInput(ctx, nil, args...)
+}
+
+// gitInput runs git with args and stdin as its standard input, returning its
+// standard output.
+func gitInput(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = stdin
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	return out, nil
+}
+
+// Filter returns the result with only the problems on changed lines. Every
+// problem in an added file is kept, including those that concern the file as
+// a whole, while files the change did not touch lose all of their problems.
+func (c *Changes) Filter(result FileResult) FileResult {
+	file, ok := c.files[path.Clean(result.Path)]
+	if !ok {
+		result.Problems = nil
+		return result
+	}
+
+	if file.added {
+		return result
+	}
+
+	var problems []lint.Problem
+
+	for _, problem := range result.Problems {
+		if file.touches(problem) {
+			problems = append(problems, problem)
+		}
+	}
+
+	result.Problems = problems
+	return result
+}
+
+func (f fileChanges) touches(problem lint.Problem) bool {
+	end := max(problem.EndLine, problem.Line)
+
+	for line := problem.Line; line <= end; line++ {
+		if _, ok := f.lines[line]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Paths filters paths down to the files the change touched.
+func (c *Changes) Paths(paths []string) []string {
+	var touched []string
+
+	for _, p := range paths {
+		if _, ok := c.files[path.Clean(p)]; ok {
+			touched = append(touched, p)
+		}
+	}
+
+	return touched
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func problemLines(problems []lint.Problem) []int {
+	var lines []int
+	for _, problem := range problems {
+		lines = append(lines, problem.Line)
+	}
+	return lines
+}
+
+func TestChangesFromPatch(t *testing.T) {
+	const patch = `--- a/changed.yaml
++++ b/changed.yaml
+@@ -2,0 +3 @@
++c: 3 
+--- /dev/null
++++ b/new.yaml
+@@ -0,0 +1,2 @@
++a: 1 
++b: 2 
+`
+
+	changes, err := ChangesFromPatch(strings.NewReader(patch))
+	assert.NoError(t, err)
+
+	t.Run("Changed Lines", func(t *testing.T) {
+		result := changes.Filter(lintResult("changed.yaml", "a: 1 \nb: 2 \nc: 3 \n"))
+		assert.Equal(t, []int{3}, problemLines(result.Problems))
+	})
+
+	t.Run("New File", func(t *testing.T) {
+		result := changes.Filter(lintResult("./new.yaml", "a: 1 \nb: 2 \n"))
+		assert.Equal(t, []int{1, 2}, problemLines(result.Problems))
+	})
+
+	t.Run("Untouched File", func(t *testing.T) {
+		result := changes.Filter(lintResult("other.yaml", "a: 1 \n"))
+		assert.Empty(t, result.Problems)
+	})
+
+	t.Run("Paths", func(t *testing.T) {
+		assert.Equal(t, []string{"changed.yaml", "new.yaml"}, changes.Paths([]string{"changed.yaml", "other.yaml", "new.yaml"}))
+	})
+}
+
+// testGitRepo creates a git repository in a temporary directory and changes
+// into it for the duration of the test.
+func testGitRepo(t *testing.T) (run func(args ...string), write func(name, src string)) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	run = func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+
+	write = func(name, src string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		assert.NoError(t, os.WriteFile(name, []byte(src), 0o644))
+	}
+
+	run("init", "-q")
+
+	return run, write
+}
+
+func TestChangesFromRev(t *testing.T) {
+	run, write := testGitRepo(t)
+
+	write("changed.yaml", "a: 1 \nb: 2\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+
+	write("changed.yaml", "a: 1 \nb: 2 \n")
+	write("untracked.yaml", "a: 1 \n")
+
+	changes, err := ChangesFromRev(context.Background(), "HEAD")
+	assert.NoError(t, err)
+
+	result := changes.Filter(lintResult("changed.yaml", "a: 1 \nb: 2 \n"))
+	assert.Equal(t, []int{2}, problemLines(result.Problems))
+
+	result = changes.Filter(lintResult("untracked.yaml", "a: 1 \n"))
+	assert.Equal(t, []int{1}, problemLines(result.Problems))
+}
@@ -0,0 +1,133 @@
+// Package runner loads the yamllintx config, discovers the files it applies
+// to and lints them, as the yamllintx command does.
+package runner
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// Level is the severity of the problems reported by a rule.
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+)
+
+// Config is a yamllintx config file.
+type Config struct {
+	YamlFiles []string            `yaml:"yaml-files"`
+	Ignore    []string            `yaml:"ignore"`
+	Rules     map[string]ast.Node `yaml:"rules"`
+
+	// hash identifies the contents of the config file.
+	hash []byte
+	// levels holds the level set for each rule that sets one.
+	levels map[string]Level
+}
+
+// LoadConfig reads and parses the config file at path.
+func LoadConfig(path string) (Config, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return ParseConfig(bytes)
+}
+
+// ParseConfig parses a config, filling in the default yaml-files patterns if
+// the config sets none.
+func ParseConfig(src []byte) (Config, error) {
+	config := Config{
+		YamlFiles: []string{
+			"*.yaml",
+			"*.yml",
+			".yamllint",
+		},
+	}
+
+	if err := yaml.Unmarshal(src, &config); err != nil {
+		return Config{}, err
+	}
+
+	hash := sha256.Sum256(src)
+	config.hash = hash[:]
+
+	config.levels = make(map[string]Level)
+
+	for rule, node := range config.Rules {
+		if node == nil {
+			continue
+		}
+
+		var opts struct {
+			Level Level `yaml:"level"`
+		}
+		if err := yaml.NodeToValue(node, &opts); err != nil {
+			continue
+		}
+
+		switch opts.Level {
+		case "":
+		case LevelError, LevelWarning:
+			config.levels[rule] = opts.Level
+		default:
+			return Config{}, fmt.Errorf("rule %s: invalid level %q", rule, opts.Level)
+		}
+	}
+
+	for rule, node := range config.Rules {
+		if factory, ok := ruleFactories[rule]; ok {
+			if _, err := factory(node); err != nil {
+				return Config{}, fmt.Errorf("rule %s: %w", rule, err)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// Hash identifies the contents the config was parsed from.
+func (c Config) Hash() []byte {
+	return c.hash
+}
+
+// Level returns the level of the problems reported under rule. Rules are
+// errors unless the config sets their level to warning, as in yamllint.
+func (c Config) Level(rule string) Level {
+	if level, ok := c.levels[rule]; ok {
+		return level
+	}
+	return LevelError
+}
+
+// Chain instantiates the linters for the rules enabled in the config, in
+// order of rule name, with the options set for them. Unknown rules, and
+// rules with options that ParseConfig would have rejected, are skipped.
+func (c Config) Chain() lint.Chain {
+	var chain lint.Chain
+
+	for _, rule := range slices.Sorted(maps.Keys(c.Rules)) {
+		factory, ok := ruleFactories[rule]
+		if !ok {
+			continue
+		}
+
+		linter, err := factory(c.Rules[rule])
+		if err != nil {
+			continue
+		}
+		chain = append(chain, linter)
+	}
+
+	return chain
+}
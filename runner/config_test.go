@@ -0,0 +1,154 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigLevel(t *testing.T) {
+	config, err := ParseConfig([]byte(`rules:
+  braces:
+    level: warning
+  brackets:
+    level: error
+  comments: enable
+  hyphens: {}
+`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, LevelWarning, config.Level("braces"))
+	assert.Equal(t, LevelError, config.Level("brackets"))
+	assert.Equal(t, LevelError, config.Level("comments"))
+	assert.Equal(t, LevelError, config.Level("hyphens"))
+	assert.Equal(t, LevelError, config.Level("syntax"))
+
+	_, err = ParseConfig([]byte("rules:\n  braces:\n    level: info\n"))
+	assert.EqualError(t, err, `rule braces: invalid level "info"`)
+}
+
+func TestConfigDefaults(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected lint.Linter
+	}{
+		{"anchors", lint.Anchors(lint.AnchorOpts{ForbidUndeclaredAliases: true})},
+		{"braces", lint.Braces{}},
+		{"brackets", lint.Brackets{}},
+		{"colons", lint.Colons{MaxSpacesBefore: 0, MaxSpacesAfter: 1}},
+		{"commas", lint.Commas{MaxSpacesBefore: 0, MinSpacesAfter: 1, MaxSpacesAfter: 1}},
+		{"comments", lint.Comments{RequireStartingSpace: true, IgnoreShebangs: true}},
+		{"document-end", lint.DocumentEnd{Present: true}},
+		{"document-start", lint.DocumentStart{Present: true}},
+		{"empty-lines", lint.EmptyLines{Max: 2, MaxStart: 0, MaxEnd: 0}},
+		{"empty-values", lint.EmptyValues{ForbidInBlockMappings: true, ForbidInFlowMappings: true, ForbidInBlockSequences: true}},
+		{"float-values", lint.FloatValues{}},
+		{"hyphens", lint.Hyphens{MaxSpacesAfter: 1}},
+		{"octal", lint.Octal{ForbidImplicitOctal: true, ForbidExplicitOctal: true}},
+		{"trailing-spaces", lint.TrailingSpaces{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			for _, value := range []string{"enable", "{}", "{level: warning}"} {
+				config, err := ParseConfig([]byte("rules:\n  " + tt.rule + ": " + value + "\n"))
+				assert.NoError(t, err)
+				assert.Equal(t, lint.Chain{tt.expected}, config.Chain(), value)
+			}
+		})
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  string
+		expected lint.Linter
+	}{
+		{
+			name:     "anchors",
+			options:  "forbid-undeclared-aliases: false\n    forbid-unused-anchors: true",
+			expected: lint.Anchors(lint.AnchorOpts{ForbidUnusedAnchors: true}),
+		},
+		{
+			name:     "braces",
+			options:  "forbid: non-empty\n    max-spaces-inside: 1",
+			expected: lint.Braces{Forbid: lint.ForbidBracesNonEmpty, MaxSpacesInside: 1, MaxSpacesInsideEmpty: 1},
+		},
+		{
+			name:     "brackets",
+			options:  "min-spaces-inside: 1\n    max-spaces-inside: 1\n    min-spaces-inside-empty: 0\n    max-spaces-inside-empty: 0",
+			expected: lint.Brackets{MinSpacesInside: 1, MaxSpacesInside: 1},
+		},
+		{
+			name:     "colons",
+			options:  "max-spaces-after: 2",
+			expected: lint.Colons{MaxSpacesAfter: 2},
+		},
+		{
+			name:     "commas",
+			options:  "max-spaces-before: -1\n    min-spaces-after: 0",
+			expected: lint.Commas{MaxSpacesBefore: -1, MaxSpacesAfter: 1},
+		},
+		{
+			name:     "comments",
+			options:  "ignore-shebangs: false",
+			expected: lint.Comments{RequireStartingSpace: true},
+		},
+		{
+			name:     "document-end",
+			options:  "present: false",
+			expected: lint.DocumentEnd{Present: false},
+		},
+		{
+			name:     "document-start",
+			options:  "present: false",
+			expected: lint.DocumentStart{Present: false},
+		},
+		{
+			name:     "empty-lines",
+			options:  "max: 1\n    max-end: 1",
+			expected: lint.EmptyLines{Max: 1, MaxEnd: 1},
+		},
+		{
+			name:     "empty-values",
+			options:  "forbid-in-flow-mappings: false",
+			expected: lint.EmptyValues{ForbidInBlockMappings: true, ForbidInBlockSequences: true},
+		},
+		{
+			name:     "float-values",
+			options:  "forbid-nan: true\n    forbid-inf: true",
+			expected: lint.FloatValues{ForbidNaN: true, ForbidInf: true},
+		},
+		{
+			name:     "hyphens",
+			options:  "max-spaces-after: 3",
+			expected: lint.Hyphens{MaxSpacesAfter: 3},
+		},
+		{
+			name:     "octal",
+			options:  "forbid-explicit-octal: false",
+			expected: lint.Octal{ForbidImplicitOctal: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte("rules:\n  " + tt.name + ":\n    level: warning\n    " + tt.options + "\n"))
+			assert.NoError(t, err)
+			assert.Equal(t, lint.Chain{tt.expected}, config.Chain())
+		})
+	}
+}
+
+func TestConfigOptionErrors(t *testing.T) {
+	_, err := ParseConfig([]byte("rules:\n  brackets:\n    forbid: sometimes\n"))
+	assert.EqualError(t, err, "rule brackets: invalid forbid value sometimes, expected true, false or non-empty")
+
+	_, err = ParseConfig([]byte("rules:\n  hyphens:\n    max-spaces-after: lots\n"))
+	assert.ErrorContains(t, err, "rule hyphens: ")
+
+	_, err = ParseConfig([]byte("rules:\n  empty-lines:\n    max: lots\n"))
+	assert.ErrorContains(t, err, "rule empty-lines: ")
+}
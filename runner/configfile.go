@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"os"
+	"sync"
+)
+
+// ConfigFile keeps a config up to date with the file it was loaded from. It
+// is safe for concurrent use.
+type ConfigFile struct {
+	path string
+
+	mu      sync.Mutex
+	checked bool
+	state   fileState
+	config  Config
+	err     error
+}
+
+// NewConfigFile returns a ConfigFile for the config file at path, which is
+// loaded by the first call to Reload.
+func NewConfigFile(path string) *ConfigFile {
+	return &ConfigFile{path: path}
+}
+
+// OpenConfigFile loads the config file at path.
+func OpenConfigFile(path string) (*ConfigFile, error) {
+	c := NewConfigFile(path)
+
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Config returns the last config that loaded successfully, or the zero
+// Config if none has.
+func (c *ConfigFile) Config() Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.config
+}
+
+// Reload loads the config again if the file changed since the last call. It
+// reports whether a new config is in use, and the error if the file could
+// not be loaded, in which case the previous config stays in use. An error is
+// only returned once for each change of the file.
+func (c *ConfigFile) Reload() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, err := statFile(os.Stat(c.path))
+	if c.checked && state == c.state && (err == nil || c.err != nil) {
+		return false, nil
+	}
+
+	if err == nil {
+		var config Config
+		config, err = LoadConfig(c.path)
+		if err == nil {
+			c.config = config
+		}
+	}
+
+	c.checked = true
+	c.state = state
+	c.err = err
+
+	return err == nil, err
+}
@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+
+	mtime := time.Now()
+	write := func(src string) {
+		assert.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+		mtime = mtime.Add(time.Second)
+		assert.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	write("rules:\n  braces: {}\n")
+
+	configFile, err := OpenConfigFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, configFile.Config().Chain(), 1)
+
+	reloaded, err := configFile.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	write("rules: [")
+
+	reloaded, err = configFile.Reload()
+	assert.Error(t, err)
+	assert.False(t, reloaded)
+	assert.Len(t, configFile.Config().Chain(), 1)
+
+	// The error is reported once per change.
+	reloaded, err = configFile.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	write("rules:\n  braces: {}\n  brackets: {}\n")
+
+	reloaded, err = configFile.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Len(t, configFile.Config().Chain(), 2)
+
+	assert.NoError(t, os.Remove(path))
+
+	_, err = configFile.Reload()
+	assert.Error(t, err)
+
+	_, err = configFile.Reload()
+	assert.NoError(t, err)
+}
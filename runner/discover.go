@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Discover walks fsys and returns the paths of the files selected by the
+// yaml-files patterns of the config and not excluded by its ignore patterns.
+// yaml-files patterns are matched against file names, ignore patterns against
+// the slash-separated path from the root of fsys.
+func Discover(fsys fs.FS, cfg Config) ([]string, error) {
+	var files []string
+
+	walkFunc := func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		ok, err := cfg.Selects(path)
+		if err != nil {
+			return err
+		}
+		if ok {
+			files = append(files, path)
+		}
+
+		return nil
+	}
+
+	if err := fs.WalkDir(fsys, ".", walkFunc); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Selects reports whether the file at the slash-separated path is one the
+// config applies to.
+func (c Config) Selects(path string) (bool, error) {
+	for _, pattern := range c.Ignore {
+		match, err := doublestar.Match(pattern, path)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return false, nil
+		}
+	}
+
+	name := path[strings.LastIndexByte(path, '/')+1:]
+
+	for _, pattern := range c.YamlFiles {
+		match, err := doublestar.Match(pattern, name)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.yaml":                 {},
+		"b.yml":                  {},
+		".yamllint":              {},
+		"README.md":              {},
+		"charts/values.yaml":     {},
+		"charts/templates/x.yml": {},
+		"vendor/lib/config.yaml": {},
+		"vendor/lib/notes.txt":   {},
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		expected []string
+	}{
+		{
+			name:   "Defaults",
+			config: "rules: {}",
+			expected: []string{
+				".yamllint",
+				"a.yaml",
+				"b.yml",
+				"charts/templates/x.yml",
+				"charts/values.yaml",
+				"vendor/lib/config.yaml",
+			},
+		},
+		{
+			name:   "Ignore",
+			config: "ignore: ['vendor/**', 'charts/templates/*']",
+			expected: []string{
+				".yamllint",
+				"a.yaml",
+				"b.yml",
+				"charts/values.yaml",
+			},
+		},
+		{
+			name:   "YamlFiles",
+			config: "yaml-files: ['*.yml']",
+			expected: []string{
+				"b.yml",
+				"charts/templates/x.yml",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tt.config))
+			assert.NoError(t, err)
+
+			files, err := Discover(fsys, config)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, files)
+		})
+	}
+}
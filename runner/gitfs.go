@@ -0,0 +1,393 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitFS is a read-only fs.FS over the files recorded in git, either in the
+// index or in the tree of a revision. Paths are relative to the working
+// directory, like those of the files on disk, and file contents are read
+// from the git object store on demand, through a single git process that is
+// started on the first read and stopped by Close.
+type GitFS struct {
+	ctx context.Context
+	// blobs maps the path of every regular file to its object id.
+	blobs map[string]string
+	dirs  map[string][]fs.DirEntry
+
+	mu    sync.Mutex
+	batch *catFile
+}
+
+// IndexFS returns the files with changes staged in the git index, as they
+// will be in the next commit. Files the commit leaves untouched are not part
+// of it, and neither are unmerged or deleted files.
+func IndexFS(ctx context.Context) (*GitFS, error) {
+	out, err := git(ctx, "diff", "--cached", "--name-only", "-z", "--no-renames", "--diff-filter=d")
+	if err != nil {
+		return nil, err
+	}
+
+	staged := make(map[string]bool)
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			staged[name] = true
+		}
+	}
+
+	out, err = git(ctx, "ls-files", "-z", "--stage")
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make(map[string]string)
+
+	// Each entry has the form "<mode> <object> <stage>\t<path>".
+	for _, entry := range strings.Split(string(out), "\x00") {
+		info, name, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(info)
+		if len(fields) != 3 || !regularMode(fields[0]) || fields[2] != "0" || !staged[name] {
+			continue
+		}
+
+		blobs[name] = fields[1]
+	}
+
+	return newGitFS(ctx, blobs)
+}
+
+// RevFS returns the files in the tree of the git revision rev, without
+// checking it out.
+func RevFS(ctx context.Context, rev string) (*GitFS, error) {
+	out, err := git(ctx, "ls-tree", "-r", "-z", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make(map[string]string)
+
+	// Each entry has the form "<mode> <type> <object>\t<path>".
+	for _, entry := range strings.Split(string(out), "\x00") {
+		info, name, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(info)
+		if len(fields) != 3 || !regularMode(fields[0]) || fields[1] != "blob" {
+			continue
+		}
+
+		blobs[name] = fields[2]
+	}
+
+	return newGitFS(ctx, blobs)
+}
+
+// regularMode reports whether a git file mode is that of a regular file, as
+// opposed to a symlink or submodule.
+func regularMode(mode string) bool {
+	return mode == "100644" || mode == "100755"
+}
+
+func newGitFS(ctx context.Context, blobs map[string]string) (*GitFS, error) {
+	sizes, err := objectSizes(ctx, blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GitFS{
+		ctx:   ctx,
+		blobs: blobs,
+		dirs:  map[string][]fs.DirEntry{".": nil},
+	}
+
+	for name, object := range blobs {
+		entry := fs.DirEntry(gitEntry{name: path.Base(name), size: sizes[object]})
+
+		for dir := path.Dir(name); ; dir = path.Dir(dir) {
+			_, seen := g.dirs[dir]
+			g.dirs[dir] = append(g.dirs[dir], entry)
+
+			if seen || dir == "." {
+				break
+			}
+			entry = gitEntry{name: path.Base(dir), dir: true}
+		}
+	}
+
+	for _, entries := range g.dirs {
+		slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+	}
+
+	return g, nil
+}
+
+// objectSizes looks up the sizes of the blobs in a single git invocation.
+func objectSizes(ctx context.Context, blobs map[string]string) (map[string]int64, error) {
+	var objects strings.Builder
+	for _, object := range blobs {
+		objects.WriteString(object + "\n")
+	}
+
+	out, err := gitInput(ctx, strings.NewReader(objects.String()), "cat-file", "--batch-check=%(objectname) %(objectsize)")
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64, len(blobs))
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		object, size, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git cat-file output: %q", line)
+		}
+		sizes[object] = n
+	}
+
+	return sizes, nil
+}
+
+// Open implements fs.FS.
+func (g *GitFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if entries, ok := g.dirs[name]; ok {
+		return &gitDir{
+			info:    gitEntry{name: path.Base(name), dir: true},
+			entries: entries,
+		}, nil
+	}
+
+	data, err := g.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitFile{
+		info:   gitEntry{name: path.Base(name), size: int64(len(data))},
+		Reader: bytes.NewReader(data),
+	}, nil
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (g *GitFS) ReadFile(name string) ([]byte, error) {
+	object, ok := g.blobs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	data, err := g.readBlob(object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	return data, nil
+}
+
+func (g *GitFS) readBlob(object string) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.batch == nil {
+		batch, err := startCatFile(g.ctx)
+		if err != nil {
+			return nil, err
+		}
+		g.batch = batch
+	}
+
+	data, err := g.batch.read(object)
+	if err != nil {
+		// The stream may be left in the middle of an object, so the next
+		// read starts over with a new process.
+		g.batch.close()
+		g.batch = nil
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Close stops the git process that reads file contents, if one was started.
+func (g *GitFS) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.batch == nil {
+		return nil
+	}
+
+	err := g.batch.close()
+	g.batch = nil
+	return err
+}
+
+// catFile streams the contents of objects out of a running
+// "git cat-file --batch".
+type catFile struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func startCatFile(ctx context.Context) (*catFile, error) {
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	return &catFile{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+// read asks for one object and reads back its contents, which git sends as
+// "<object> <type> <size>\n<contents>\n".
+func (c *catFile) read(object string) ([]byte, error) {
+	if _, err := io.WriteString(c.stdin, object+"\n"); err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	header, err := c.stdout.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected git cat-file output: %q", header)
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected git cat-file output: %q", header)
+	}
+
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(c.stdout, data); err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+
+	return data[:size], nil
+}
+
+func (c *catFile) close() error {
+	err := c.stdin.Close()
+
+	// Drain whatever git still has to say, so that it is not left blocked
+	// writing to a pipe nobody reads.
+	_, _ = io.Copy(io.Discard, c.stdout)
+
+	if waitErr := c.cmd.Wait(); waitErr != nil {
+		err = errors.Join(err, fmt.Errorf("git: %w", waitErr))
+	}
+	return err
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (g *GitFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, ok := g.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return slices.Clone(entries), nil
+}
+
+// gitEntry describes a file or directory of a GitFS. It serves as both its
+// fs.DirEntry and its fs.FileInfo.
+type gitEntry struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (e gitEntry) Name() string               { return e.name }
+func (e gitEntry) Size() int64                { return e.size }
+func (e gitEntry) ModTime() time.Time         { return time.Time{} }
+func (e gitEntry) IsDir() bool                { return e.dir }
+func (e gitEntry) Sys() any                   { return nil }
+func (e gitEntry) Type() fs.FileMode          { return e.Mode().Type() }
+func (e gitEntry) Info() (fs.FileInfo, error) { return e, nil }
+
+func (e gitEntry) Mode() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+type gitFile struct {
+	info gitEntry
+	*bytes.Reader
+}
+
+func (f *gitFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *gitFile) Close() error               { return nil }
+
+type gitDir struct {
+	info    gitEntry
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *gitDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *gitDir) Close() error               { return nil }
+
+func (d *gitDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *gitDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return slices.Clone(rest), nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.offset += n
+	return slices.Clone(rest[:n]), nil
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitFS(t *testing.T) {
+	run, write := testGitRepo(t)
+
+	write("a.yaml", "a: 1\n")
+	write("dir/b.yml", "b: 2\n")
+	write("dir/c.txt", "c\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+
+	write("a.yaml", "a: staged\n")
+	write("dir/d.yaml", "d: 4\n")
+	run("add", "a.yaml", "dir/d.yaml")
+	run("rm", "-q", "dir/c.txt")
+	write("a.yaml", "a: working tree\n")
+	write("untracked.yaml", "u: 1\n")
+
+	cfg := Config{YamlFiles: []string{"*.yaml", "*.yml"}, Ignore: []string{"dir/*.yml"}}
+
+	t.Run("Index", func(t *testing.T) {
+		fsys, err := IndexFS(context.Background())
+		assert.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, fsys.Close()) })
+
+		// Only files with staged changes are part of it.
+		assert.NoError(t, fstest.TestFS(fsys, "a.yaml", "dir/d.yaml"))
+
+		data, err := fs.ReadFile(fsys, "a.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, "a: staged\n", string(data))
+
+		_, err = fs.ReadFile(fsys, "dir/b.yml")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+
+		files, err := Discover(fsys, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.yaml", "dir/d.yaml"}, files)
+	})
+
+	t.Run("Revision", func(t *testing.T) {
+		fsys, err := RevFS(context.Background(), "HEAD")
+		assert.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, fsys.Close()) })
+		assert.NoError(t, fstest.TestFS(fsys, "a.yaml", "dir/b.yml", "dir/c.txt"))
+
+		data, err := fs.ReadFile(fsys, "a.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, "a: 1\n", string(data))
+
+		_, err = fs.ReadFile(fsys, "untracked.yaml")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("Unknown Revision", func(t *testing.T) {
+		_, err := RevFS(context.Background(), "does-not-exist")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,291 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// ruleFactories instantiate the linter for each rule from the options set
+// for it in the config. Options that are not set take the defaults of
+// yamllint, so that a rule enabled without options checks what it would
+// there.
+var ruleFactories = map[string]func(ast.Node) (lint.Linter, error){
+	"anchors": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			ForbidUndeclaredAliases bool `yaml:"forbid-undeclared-aliases"`
+			ForbidDuplicatedAnchors bool `yaml:"forbid-duplicated-anchors"`
+			ForbidUnusedAnchors     bool `yaml:"forbid-unused-anchors"`
+		}{
+			ForbidUndeclaredAliases: true,
+			ForbidDuplicatedAnchors: false,
+			ForbidUnusedAnchors:     false,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.Anchors(lint.AnchorOpts{
+			ForbidUndeclaredAliases: opts.ForbidUndeclaredAliases,
+			ForbidDuplicatedAnchors: opts.ForbidDuplicatedAnchors,
+			ForbidUnusedAnchors:     opts.ForbidUnusedAnchors,
+		}), nil
+	},
+	"braces": func(node ast.Node) (lint.Linter, error) {
+		opts := defaultCollectionOptions
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		forbid, err := parseForbid(opts.Forbid, lint.ForbidBracesNone, lint.ForbidBracesAll, lint.ForbidBracesNonEmpty)
+		if err != nil {
+			return nil, err
+		}
+
+		return lint.Braces{
+			Forbid:               forbid,
+			MinSpacesInside:      opts.MinSpacesInside,
+			MaxSpacesInside:      opts.MaxSpacesInside,
+			MinSpacesInsideEmpty: inherit(opts.MinSpacesInsideEmpty, opts.MinSpacesInside),
+			MaxSpacesInsideEmpty: inherit(opts.MaxSpacesInsideEmpty, opts.MaxSpacesInside),
+		}, nil
+	},
+	"brackets": func(node ast.Node) (lint.Linter, error) {
+		opts := defaultCollectionOptions
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		forbid, err := parseForbid(opts.Forbid, lint.ForbidBracketsNone, lint.ForbidBracketsAll, lint.ForbidBracketsNonEmpty)
+		if err != nil {
+			return nil, err
+		}
+
+		return lint.Brackets{
+			Forbid:               forbid,
+			MinSpacesInside:      opts.MinSpacesInside,
+			MaxSpacesInside:      opts.MaxSpacesInside,
+			MinSpacesInsideEmpty: inherit(opts.MinSpacesInsideEmpty, opts.MinSpacesInside),
+			MaxSpacesInsideEmpty: inherit(opts.MaxSpacesInsideEmpty, opts.MaxSpacesInside),
+		}, nil
+	},
+	"colons": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			MaxSpacesBefore int `yaml:"max-spaces-before"`
+			MaxSpacesAfter  int `yaml:"max-spaces-after"`
+		}{
+			MaxSpacesBefore: 0,
+			MaxSpacesAfter:  1,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.Colons{
+			MaxSpacesBefore: opts.MaxSpacesBefore,
+			MaxSpacesAfter:  opts.MaxSpacesAfter,
+		}, nil
+	},
+	"commas": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			MaxSpacesBefore int `yaml:"max-spaces-before"`
+			MinSpacesAfter  int `yaml:"min-spaces-after"`
+			MaxSpacesAfter  int `yaml:"max-spaces-after"`
+		}{
+			MaxSpacesBefore: 0,
+			MinSpacesAfter:  1,
+			MaxSpacesAfter:  1,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.Commas{
+			MaxSpacesBefore: opts.MaxSpacesBefore,
+			MinSpacesAfter:  opts.MinSpacesAfter,
+			MaxSpacesAfter:  opts.MaxSpacesAfter,
+		}, nil
+	},
+	"comments": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			RequireStartingSpace bool `yaml:"require-starting-space"`
+			IgnoreShebangs       bool `yaml:"ignore-shebangs"`
+		}{
+			RequireStartingSpace: true,
+			IgnoreShebangs:       true,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.Comments{
+			RequireStartingSpace: opts.RequireStartingSpace,
+			IgnoreShebangs:       opts.IgnoreShebangs,
+		}, nil
+	},
+	"document-end": func(node ast.Node) (lint.Linter, error) {
+		opts := presentOptions{Present: true}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.DocumentEnd{Present: opts.Present}, nil
+	},
+	"document-start": func(node ast.Node) (lint.Linter, error) {
+		opts := presentOptions{Present: true}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.DocumentStart{Present: opts.Present}, nil
+	},
+	"empty-lines": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			Max      int `yaml:"max"`
+			MaxStart int `yaml:"max-start"`
+			MaxEnd   int `yaml:"max-end"`
+		}{
+			Max:      2,
+			MaxStart: 0,
+			MaxEnd:   0,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.EmptyLines{
+			Max:      opts.Max,
+			MaxStart: opts.MaxStart,
+			MaxEnd:   opts.MaxEnd,
+		}, nil
+	},
+	"empty-values": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			ForbidInBlockMappings  bool `yaml:"forbid-in-block-mappings"`
+			ForbidInFlowMappings   bool `yaml:"forbid-in-flow-mappings"`
+			ForbidInBlockSequences bool `yaml:"forbid-in-block-sequences"`
+		}{
+			ForbidInBlockMappings:  true,
+			ForbidInFlowMappings:   true,
+			ForbidInBlockSequences: true,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.EmptyValues{
+			ForbidInBlockMappings:  opts.ForbidInBlockMappings,
+			ForbidInFlowMappings:   opts.ForbidInFlowMappings,
+			ForbidInBlockSequences: opts.ForbidInBlockSequences,
+		}, nil
+	},
+	"float-values": func(node ast.Node) (lint.Linter, error) {
+		var opts struct {
+			RequireNumeralBeforeDecimal bool `yaml:"require-numeral-before-decimal"`
+			ForbidScientificNotation    bool `yaml:"forbid-scientific-notation"`
+			ForbidNaN                   bool `yaml:"forbid-nan"`
+			ForbidInf                   bool `yaml:"forbid-inf"`
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.FloatValues{
+			RequireNumeralBeforeDecimal: opts.RequireNumeralBeforeDecimal,
+			ForbidScientificNotation:    opts.ForbidScientificNotation,
+			ForbidNaN:                   opts.ForbidNaN,
+			ForbidInf:                   opts.ForbidInf,
+		}, nil
+	},
+	"hyphens": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			MaxSpacesAfter int `yaml:"max-spaces-after"`
+		}{
+			MaxSpacesAfter: 1,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.Hyphens{MaxSpacesAfter: opts.MaxSpacesAfter}, nil
+	},
+	"octal": func(node ast.Node) (lint.Linter, error) {
+		opts := struct {
+			ForbidImplicitOctal bool `yaml:"forbid-implicit-octal"`
+			ForbidExplicitOctal bool `yaml:"forbid-explicit-octal"`
+		}{
+			ForbidImplicitOctal: true,
+			ForbidExplicitOctal: true,
+		}
+		if err := decodeOptions(node, &opts); err != nil {
+			return nil, err
+		}
+
+		return lint.Octal{
+			ForbidImplicitOctal: opts.ForbidImplicitOctal,
+			ForbidExplicitOctal: opts.ForbidExplicitOctal,
+		}, nil
+	},
+	"trailing-spaces": func(node ast.Node) (lint.Linter, error) {
+		return lint.TrailingSpaces{}, nil
+	},
+}
+
+// collectionOptions are the options of braces and brackets.
+type collectionOptions struct {
+	// Forbid is false, true or "non-empty".
+	Forbid          any `yaml:"forbid"`
+	MinSpacesInside int `yaml:"min-spaces-inside"`
+	MaxSpacesInside int `yaml:"max-spaces-inside"`
+	// The spaces inside empty collections are -1 to take those of
+	// non-empty ones.
+	MinSpacesInsideEmpty int `yaml:"min-spaces-inside-empty"`
+	MaxSpacesInsideEmpty int `yaml:"max-spaces-inside-empty"`
+}
+
+var defaultCollectionOptions = collectionOptions{
+	Forbid:               false,
+	MinSpacesInside:      0,
+	MaxSpacesInside:      0,
+	MinSpacesInsideEmpty: -1,
+	MaxSpacesInsideEmpty: -1,
+}
+
+// inherit returns fallback for an option set to -1.
+func inherit(value, fallback int) int {
+	if value == -1 {
+		return fallback
+	}
+	return value
+}
+
+// presentOptions are the options of document-start and document-end.
+type presentOptions struct {
+	Present bool `yaml:"present"`
+}
+
+// decodeOptions fills opts from the options set for a rule. A rule that is
+// enabled without options, as in "comments: enable", sets none.
+func decodeOptions(node ast.Node, opts any) error {
+	switch node.(type) {
+	case *ast.MappingNode, *ast.MappingValueNode:
+		return yaml.NodeToValue(node, opts)
+	default:
+		return nil
+	}
+}
+
+func parseForbid[T ~int](value any, none, all, nonEmpty T) (T, error) {
+	switch value {
+	case nil, false:
+		return none, nil
+	case true:
+		return all, nil
+	case "non-empty":
+		return nonEmpty, nil
+	default:
+		return none, fmt.Errorf("invalid forbid value %v, expected true, false or non-empty", value)
+	}
+}
@@ -0,0 +1,302 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/cedws/yamllintx/internal/diff"
+	"github.com/cedws/yamllintx/lint"
+)
+
+// Mode selects what Run does with the problems it finds.
+type Mode int
+
+const (
+	// ModeLint reports the problems in each file.
+	ModeLint Mode = iota
+	// ModeFix rewrites each file with its fixable problems resolved and
+	// reports the problems that remain.
+	ModeFix
+	// ModeDiff reports the fixes for each file as a unified diff, without
+	// writing anything.
+	ModeDiff
+)
+
+// FileResult is the outcome of linting a single file.
+type FileResult struct {
+	Path string
+	// Mode is what was done with the problems in the file.
+	Mode Mode
+	// Source is the content that was linted.
+	Source   []byte
+	Problems []lint.Problem
+	// Diff holds the unified diff of the fixes in ModeDiff, or is empty if
+	// there is nothing to fix.
+	Diff string
+	// TimedOut is set if linting the file did not finish within the time
+	// budget. Problems then holds only the timeout problem.
+	TimedOut bool
+	// Err is set if the file could not be read or written.
+	Err error
+}
+
+type options struct {
+	mode    Mode
+	jobs    int
+	timeout time.Duration
+	cache   *Cache
+	fsys    fs.FS
+}
+
+// Option configures Run.
+type Option func(*options)
+
+// WithMode sets what Run does with the problems it finds. The default is
+// ModeLint.
+func WithMode(mode Mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithJobs sets the number of files linted concurrently. The default is
+// GOMAXPROCS.
+func WithJobs(jobs int) Option {
+	return func(o *options) {
+		o.jobs = jobs
+	}
+}
+
+// WithTimeout sets a time budget for each file. Files that exceed it are
+// reported with a problem instead of their results, even while the lexer or
+// parser is still busy with them. Zero means no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithCache reuses the results cached for unchanged files in ModeLint, and
+// caches new ones.
+func WithCache(cache *Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithFS reads files from fsys instead of the operating system. Paths are
+// then interpreted as paths in fsys.
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
+
+// Run lints the files at the given paths with the rules enabled in the
+// config. Results are yielded in the order of the paths, while the files are
+// processed concurrently. Stopping the iteration or cancelling ctx stops the
+// run.
+func Run(ctx context.Context, cfg Config, paths []string, opts ...Option) iter.Seq[FileResult] {
+	o := options{
+		jobs: runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	chain := cfg.Chain()
+
+	process := func(path string) FileResult {
+		return o.process(ctx, chain, path)
+	}
+
+	return lintFiles(ctx, paths, o.jobs, process)
+}
+
+func (o options) readFile(path string) ([]byte, error) {
+	if o.fsys != nil {
+		return fs.ReadFile(o.fsys, path)
+	}
+	return os.ReadFile(path)
+}
+
+func (o options) process(ctx context.Context, chain lint.Chain, path string) FileResult {
+	result := FileResult{Path: path, Mode: o.mode}
+
+	bytes, err := o.readFile(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Source = bytes
+
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	switch {
+	case o.mode == ModeDiff:
+		result.Diff, err = bounded(ctx, func() (string, error) {
+			return diffFile(ctx, path, bytes, chain)
+		})
+	case o.mode == ModeFix:
+		var fixed fixedFile
+		fixed, err = bounded(ctx, func() (fixedFile, error) {
+			return fixFile(ctx, bytes, chain)
+		})
+		if err == nil {
+			result.Problems = fixed.problems
+			err = writeFixed(path, bytes, fixed.src)
+		}
+	case o.cache != nil:
+		problems, ok := o.cache.get(bytes)
+		if !ok {
+			problems, err = LintSource(ctx, bytes, chain)
+			if err == nil {
+				o.cache.put(bytes, problems)
+			}
+		}
+		result.Problems = problems
+	default:
+		result.Problems, err = LintSource(ctx, bytes, chain)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) && o.timeout > 0 {
+		result.Problems = append(result.Problems, lint.TimeoutProblem(bytes, o.timeout))
+		result.TimedOut = true
+	} else if err != nil {
+		result.Err = err
+	}
+
+	return result
+}
+
+// LintSource lints src with the chain like lint.LintContext, but returns as
+// soon as ctx is done even while src is still being lexed or parsed.
+func LintSource(ctx context.Context, src []byte, chain lint.Chain) ([]lint.Problem, error) {
+	return bounded(ctx, func() ([]lint.Problem, error) {
+		return lint.LintContext(ctx, src, chain...)
+	})
+}
+
+// bounded runs f and waits for it until ctx is done. Lexing and parsing
+// cannot be interrupted, so f runs in a goroutine that is abandoned rather
+// than waited for if it overruns, leaving it to finish in the background.
+// Anything with side effects belongs after bounded returns.
+func bounded[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := f()
+		done <- outcome{value: value, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.value, out.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// lintFiles processes the files on a pool of workers and yields the results
+// in the order of the files. At most jobs files are processed at once, and
+// at most jobs finished results wait to be yielded.
+func lintFiles(ctx context.Context, files []string, jobs int, process func(string) FileResult) iter.Seq[FileResult] {
+	jobs = max(jobs, 1)
+
+	return func(yield func(FileResult) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		pending := make(chan chan FileResult, jobs)
+
+		go func() {
+			defer close(pending)
+			workers := make(chan struct{}, jobs)
+
+			for _, file := range files {
+				result := make(chan FileResult, 1)
+
+				select {
+				case pending <- result:
+				case <-ctx.Done():
+					return
+				}
+
+				select {
+				case workers <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				go func() {
+					defer func() { <-workers }()
+					result <- process(file)
+				}()
+			}
+		}()
+
+		for result := range pending {
+			if !yield(<-result) {
+				return
+			}
+		}
+	}
+}
+
+// diffFile returns a unified diff of the fixes for the file, suitable for git
+// apply, without writing anything.
+func diffFile(ctx context.Context, file string, src []byte, chain lint.Chain) (string, error) {
+	fixed, _, err := lint.FixContext(ctx, src, chain...)
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.ToSlash(filepath.Clean(file))
+	return diff.Unified("a/"+name, "b/"+name, src, fixed), nil
+}
+
+type fixedFile struct {
+	src      []byte
+	problems []lint.Problem
+}
+
+// fixFile resolves all fixable problems in the source, returning the fixed
+// source and the problems that could not be fixed automatically.
+func fixFile(ctx context.Context, src []byte, chain lint.Chain) (fixedFile, error) {
+	fixed, problems, err := lint.FixContext(ctx, src, chain...)
+	if err != nil {
+		return fixedFile{}, err
+	}
+
+	return fixedFile{src: fixed, problems: problems}, nil
+}
+
+// writeFixed rewrites the file with its fixed source, if anything changed.
+func writeFixed(file string, src, fixed []byte) error {
+	if bytes.Equal(fixed, src) {
+		return nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, fixed, info.Mode().Perm())
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/cedws/yamllintx/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintFiles(t *testing.T) {
+	var files []string
+	for i := range 100 {
+		files = append(files, fmt.Sprintf("file%d.yaml", i))
+	}
+
+	process := func(file string) FileResult {
+		time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+		return FileResult{Path: file}
+	}
+
+	t.Run("Order", func(t *testing.T) {
+		var got []string
+		for result := range lintFiles(context.Background(), files, 8, process) {
+			got = append(got, result.Path)
+		}
+
+		assert.Equal(t, files, got)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		var got []string
+		for result := range lintFiles(context.Background(), files, 8, process) {
+			got = append(got, result.Path)
+			if len(got) == 10 {
+				break
+			}
+		}
+
+		assert.Equal(t, files[:10], got)
+	})
+}
+
+func TestRun(t *testing.T) {
+	fsys := fstest.MapFS{
+		"clean.yaml": {Data: []byte("key: value\n")},
+		"dirty.yaml": {Data: []byte("key: value \nlist: [ a ]\n")},
+	}
+
+	config, err := ParseConfig([]byte("rules:\n  trailing-spaces: {}\n  brackets: {}\n"))
+	assert.NoError(t, err)
+
+	paths := []string{"clean.yaml", "dirty.yaml"}
+
+	t.Run("Lint", func(t *testing.T) {
+		var results []FileResult
+		for result := range Run(context.Background(), config, paths, WithFS(fsys)) {
+			results = append(results, result)
+		}
+
+		if assert.Len(t, results, 2) {
+			assert.Equal(t, "clean.yaml", results[0].Path)
+			assert.Empty(t, results[0].Problems)
+
+			assert.Equal(t, "dirty.yaml", results[1].Path)
+			assert.Len(t, results[1].Problems, 3)
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		var diffs []string
+		for result := range Run(context.Background(), config, paths, WithFS(fsys), WithMode(ModeDiff)) {
+			assert.NoError(t, result.Err)
+			diffs = append(diffs, result.Diff)
+		}
+
+		assert.Equal(t, []string{"", `--- a/dirty.yaml
++++ b/dirty.yaml
+@@ -1,2 +1,2 @@
+-key: value 
+-list: [ a ]
++key: value
++list: [a]
+`}, diffs)
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		for result := range Run(context.Background(), config, []string{"missing.yaml"}, WithFS(fsys)) {
+			assert.Error(t, result.Err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		for result := range Run(context.Background(), config, paths, WithFS(fsys), WithTimeout(time.Nanosecond)) {
+			assert.NoError(t, result.Err)
+			if assert.Len(t, result.Problems, 1) {
+				problem := result.Problems[0]
+				assert.Equal(t, "timeout", problem.Rule)
+				assert.ErrorIs(t, problem.Error, lint.ErrTimeout)
+				assert.Equal(t, "linting did not finish within 1ns", problem.Message())
+				assert.Equal(t, 2, problem.EndColumn)
+				assert.Equal(t, 1, problem.EndOffset)
+			}
+		}
+	})
+}
+
+func TestBounded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	// The work ignores ctx, like a lexer busy with a pathological file.
+	_, err := bounded(ctx, func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	value, err := bounded(context.Background(), func() (int, error) {
+		return 1, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
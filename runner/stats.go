@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/cedws/yamllintx/lint"
+)
+
+// Stats summarizes a run: what was linted, what was found, and where the
+// time went. It is not safe for concurrent use, apart from the context
+// returned by Context.
+type Stats struct {
+	config            Config
+	files             int
+	filesWithProblems int
+	byRule            map[string]int
+	byLevel           map[Level]int
+	timings           lint.Timings
+}
+
+// NewStats returns empty stats for a run with the config, which sets the
+// level of each rule.
+func NewStats(config Config) *Stats {
+	return &Stats{
+		config:  config,
+		byRule:  make(map[string]int),
+		byLevel: make(map[Level]int),
+	}
+}
+
+// Context returns a context that makes the linting done under it, such as
+// by Run, record its timings in the stats.
+func (s *Stats) Context(ctx context.Context) context.Context {
+	return lint.WithTimings(ctx, &s.timings)
+}
+
+// Add counts the file and the problems of the result.
+func (s *Stats) Add(result FileResult) {
+	s.files++
+
+	if len(result.Problems) > 0 {
+		s.filesWithProblems++
+	}
+
+	for _, problem := range result.Problems {
+		s.byRule[problem.Rule]++
+		s.byLevel[s.config.Level(problem.Rule)]++
+	}
+}
+
+type statsJSON struct {
+	Files             int                   `json:"files"`
+	FilesWithProblems int                   `json:"filesWithProblems"`
+	Problems          int                   `json:"problems"`
+	ProblemsByRule    map[string]int        `json:"problemsByRule"`
+	ProblemsByLevel   map[Level]int         `json:"problemsByLevel"`
+	LexSeconds        float64               `json:"lexSeconds"`
+	Linters           map[string]linterJSON `json:"linters"`
+}
+
+type linterJSON struct {
+	CheckTokenSeconds float64 `json:"checkTokenSeconds"`
+	CheckLineSeconds  float64 `json:"checkLineSeconds"`
+	CheckFileSeconds  float64 `json:"checkFileSeconds"`
+}
+
+// WriteJSON writes the stats as a JSON object, with times in seconds.
+func (s *Stats) WriteJSON(w io.Writer) error {
+	out := statsJSON{
+		Files:             s.files,
+		FilesWithProblems: s.filesWithProblems,
+		Problems:          s.problems(),
+		ProblemsByRule:    s.byRule,
+		ProblemsByLevel:   s.byLevel,
+		LexSeconds:        s.timings.Lex().Seconds(),
+		Linters:           make(map[string]linterJSON),
+	}
+
+	for name, timings := range s.timings.Linters() {
+		out.Linters[name] = linterJSON{
+			CheckTokenSeconds: timings.CheckToken.Seconds(),
+			CheckLineSeconds:  timings.CheckLine.Seconds(),
+			CheckFileSeconds:  timings.CheckFile.Seconds(),
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteText writes the stats as aligned text for people to read.
+func (s *Stats) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "files scanned\t%d\n", s.files)
+	fmt.Fprintf(tw, "files with problems\t%d\n", s.filesWithProblems)
+	fmt.Fprintf(tw, "problems\t%d\n", s.problems())
+
+	for _, level := range slices.Sorted(maps.Keys(s.byLevel)) {
+		fmt.Fprintf(tw, "  level %s\t%d\n", level, s.byLevel[level])
+	}
+	for _, rule := range slices.Sorted(maps.Keys(s.byRule)) {
+		fmt.Fprintf(tw, "  rule %s\t%d\n", rule, s.byRule[rule])
+	}
+
+	fmt.Fprintf(tw, "lex time\t%s\n", s.timings.Lex())
+
+	linters := s.timings.Linters()
+	if len(linters) > 0 {
+		fmt.Fprintf(tw, "\nlinter\tCheckToken\tCheckLine\tCheckFile\n")
+		for _, name := range slices.Sorted(maps.Keys(linters)) {
+			timings := linters[name]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, timings.CheckToken, timings.CheckLine, timings.CheckFile)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (s *Stats) problems() int {
+	var n int
+	for _, count := range s.byRule {
+		n += count
+	}
+	return n
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"clean.yaml":  {Data: []byte("key: value\n")},
+		"dirty.yaml":  {Data: []byte("key: value \nlist: [ a ]\n")},
+		"broken.yaml": {Data: []byte("key: [\n")},
+	}
+
+	config, err := ParseConfig([]byte("rules:\n  trailing-spaces:\n    level: warning\n  brackets: {}\n"))
+	assert.NoError(t, err)
+
+	stats := NewStats(config)
+
+	paths := []string{"broken.yaml", "clean.yaml", "dirty.yaml"}
+	for result := range Run(stats.Context(context.Background()), config, paths, WithFS(fsys)) {
+		assert.NoError(t, result.Err)
+		stats.Add(result)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteJSON(&buf))
+
+	var got statsJSON
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, 3, got.Files)
+	assert.Equal(t, 2, got.FilesWithProblems)
+	assert.Equal(t, 4, got.Problems)
+	assert.Equal(t, map[string]int{"brackets": 2, "syntax": 1, "trailing-spaces": 1}, got.ProblemsByRule)
+	assert.Equal(t, map[Level]int{LevelError: 3, LevelWarning: 1}, got.ProblemsByLevel)
+	assert.Positive(t, got.LexSeconds)
+	assert.Contains(t, got.Linters, "brackets")
+	assert.Contains(t, got.Linters, "trailing-spaces")
+
+	buf.Reset()
+	assert.NoError(t, stats.WriteText(&buf))
+	assert.Contains(t, buf.String(), "files with problems     2\n")
+	assert.Contains(t, buf.String(), "  level warning         1\n")
+}
@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"context"
+	"io/fs"
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+// WatchRound is what Watch yields each time the files or the config change.
+type WatchRound struct {
+	// Results holds the latest result of every file the config selects,
+	// sorted by path.
+	Results []FileResult
+	// Linted holds the paths of the files linted in this round.
+	Linted []string
+	// ConfigReloaded is set if the config file changed since the last round.
+	ConfigReloaded bool
+	// Err is set if the config could not be reloaded or the files could not
+	// be discovered. The previous config stays in use.
+	Err error
+}
+
+// fileState is what polling compares to notice that a file changed.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watch lints the files selected by the config at configPath, then polls
+// them and the config every interval and yields a new round whenever
+// something changed. Only the files that changed are linted again, unless
+// the config changed. Polling works on every file system, including the
+// bind mounts of containers where change notifications are unreliable.
+//
+// Watch runs until ctx is cancelled or the iteration is stopped. The options
+// are passed on to Run.
+func Watch(ctx context.Context, configPath string, interval time.Duration, opts ...Option) iter.Seq[WatchRound] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	fsys := o.fsys
+	if fsys == nil {
+		fsys = os.DirFS(".")
+	}
+
+	return func(yield func(WatchRound) bool) {
+		configFile, err := OpenConfigFile(configPath)
+		if err != nil {
+			yield(WatchRound{Err: err})
+			return
+		}
+
+		var (
+			config  = configFile.Config()
+			states  = make(map[string]fileState)
+			results = make(map[string]FileResult)
+			first   = true
+		)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; ; first = false {
+			var round WatchRound
+
+			if !first {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+
+				reloaded, err := configFile.Reload()
+				if err != nil {
+					// A config that is being edited may not parse yet. Keep
+					// going with the previous one.
+					if !yield(WatchRound{Err: err}) {
+						return
+					}
+					continue
+				}
+
+				if reloaded {
+					config = configFile.Config()
+					round.ConfigReloaded = true
+					clear(states)
+				}
+			}
+
+			files, err := Discover(fsys, config)
+			if err != nil {
+				if !yield(WatchRound{Err: err}) {
+					return
+				}
+				continue
+			}
+
+			current := make(map[string]fileState, len(files))
+			for _, file := range files {
+				state, err := statFile(fs.Stat(fsys, file))
+				if err != nil {
+					continue
+				}
+				current[file] = state
+
+				if previous, ok := states[file]; !ok || previous != state {
+					round.Linted = append(round.Linted, file)
+				}
+			}
+
+			var removed bool
+			for file := range results {
+				if _, ok := current[file]; !ok {
+					delete(results, file)
+					removed = true
+				}
+			}
+			states = current
+
+			if !first && !removed && len(round.Linted) == 0 {
+				continue
+			}
+
+			for result := range Run(ctx, config, round.Linted, opts...) {
+				results[result.Path] = result
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			for _, file := range slices.Sorted(maps.Keys(results)) {
+				round.Results = append(round.Results, results[file])
+			}
+
+			if !yield(round) {
+				return
+			}
+		}
+	}
+}
+
+func statFile(info fs.FileInfo, err error) (fileState, error) {
+	if err != nil {
+		return fileState{}, err
+	}
+	return fileState{size: info.Size(), modTime: info.ModTime()}, nil
+}
@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+
+	// Every write moves the modification time forward explicitly, as the
+	// clock may not tick between writes.
+	mtime := time.Now()
+	write := func(name, src string) {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+		mtime = mtime.Add(time.Second)
+		assert.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	write("config.yml", "yaml-files: ['*.yaml']\nrules:\n  trailing-spaces: {}\n")
+	write("a.yaml", "a: 1 \n")
+	write("b.yaml", "b: 1\n")
+
+	type summary struct {
+		linted   []string
+		problems map[string]int
+		reloaded bool
+		err      bool
+	}
+
+	steps := []func(){
+		func() { write("b.yaml", "b: 1 \n") },
+		func() { write("config.yml", "rules: [") },
+		func() { write("config.yml", "yaml-files: ['*.yaml']\nrules:\n  brackets: {}\n") },
+		func() { assert.NoError(t, os.Remove(filepath.Join(dir, "a.yaml"))) },
+	}
+
+	var got []summary
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for round := range Watch(ctx, configPath, time.Millisecond, WithFS(os.DirFS(dir))) {
+		s := summary{
+			linted:   round.Linted,
+			problems: make(map[string]int),
+			reloaded: round.ConfigReloaded,
+			err:      round.Err != nil,
+		}
+		for _, result := range round.Results {
+			s.problems[result.Path] = len(result.Problems)
+		}
+		got = append(got, s)
+
+		if len(got) > len(steps) {
+			break
+		}
+		steps[len(got)-1]()
+	}
+
+	assert.Equal(t, []summary{
+		{linted: []string{"a.yaml", "b.yaml"}, problems: map[string]int{"a.yaml": 1, "b.yaml": 0}},
+		{linted: []string{"b.yaml"}, problems: map[string]int{"a.yaml": 1, "b.yaml": 1}},
+		{problems: map[string]int{}, err: true},
+		{linted: []string{"a.yaml", "b.yaml"}, problems: map[string]int{"a.yaml": 0, "b.yaml": 0}, reloaded: true},
+		{problems: map[string]int{"b.yaml": 0}},
+	}, got)
+}